@@ -1,6 +1,8 @@
 package apiserver
 
 import (
+	"os"
+
 	"github.com/yanking/micro-zero/pkg/app"
 	"github.com/yanking/micro-zero/pkg/config"
 )
@@ -8,8 +10,8 @@ import (
 func NewApp(name string) *app.App {
 	cfg := config.New()
 
-	// 创建默认组件运行器
-	defaultComponentRunner := app.NewDefaultComponentRunner(cfg)
+	// 创建默认组件运行器，grpc-gateway 模式下提供生成的 OpenAPI 文档
+	defaultComponentRunner := app.NewDefaultComponentRunner(cfg, app.WithOpenAPI(os.DirFS("api/openapi")))
 
 	appl := app.NewApp(name, "",
 		app.WithOptions(cfg),
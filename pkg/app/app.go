@@ -2,9 +2,12 @@ package app
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -14,9 +17,12 @@ import (
 	cliflag "k8s.io/component-base/cli/flag"
 	"k8s.io/component-base/term"
 
+	"github.com/yanking/micro-zero/pkg/components/healthserver"
 	"github.com/yanking/micro-zero/pkg/config"
 	"github.com/yanking/micro-zero/pkg/container"
+	"github.com/yanking/micro-zero/pkg/healthz"
 	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/metrics"
 	genericoptions "github.com/yanking/micro-zero/pkg/options"
 	"github.com/yanking/micro-zero/pkg/version"
 )
@@ -54,6 +60,13 @@ type App struct {
 	componentRunner ComponentRunner
 
 	contextExtractors map[string]func(context.Context) string
+	fieldExtractors   []log.FieldExtractor
+
+	// container 是组件运行器使用的容器，配置重新加载时用于通知组件
+	container *container.Container
+	// current 是当前生效的配置，配置重新加载时与新配置对比
+	current  any
+	reloadMu sync.Mutex
 }
 
 // RunFunc defines the application's startup callback function.
@@ -89,24 +102,33 @@ func WithDescription(desc string) Option {
 }
 
 // WithHealthCheckFunc is used to set the health check function for the application.
-// The container framework will use the function to start a health check server.
+// The function is called before the run function of applications without a component
+// runner. Applications running components serve the container's /livez, /readyz and
+// /healthz endpoints from the health server component instead.
 func WithHealthCheckFunc(fn HealthCheckFunc) Option {
 	return func(app *App) {
 		app.healthCheckFunc = fn
 	}
 }
 
-// WithDefaultHealthCheckFunc set the default health check function.
+// WithDefaultHealthCheckFunc set the default health check function. It starts
+// a health server serving the health check endpoint configured by the default
+// HealthOptions.
+//
+// Deprecated: applications running components serve the container's /livez,
+// /readyz and /healthz endpoints from the health server component. Use
+// WithHealthCheckFunc to run a custom check instead.
 func WithDefaultHealthCheckFunc() Option {
-	fn := func() HealthCheckFunc {
-		return func() error {
-			go genericoptions.NewHealthOptions().ServeHealthCheck()
-
-			return nil
+	return WithHealthCheckFunc(func() error {
+		opts := genericoptions.NewHealthOptions()
+		server, err := healthserver.New(opts, healthserver.WithHandlers(func(mux *http.ServeMux) {
+			healthz.InstallPathHandler(mux, opts.HealthCheckPath, healthz.Checks())
+		}))
+		if err != nil {
+			return err
 		}
-	}
-
-	return WithHealthCheckFunc(fn())
+		return server.Start(context.Background())
+	})
 }
 
 // WithSilence sets the application to silent mode, in which the program startup
@@ -166,6 +188,13 @@ func WithLoggerContextExtractor(contextExtractors map[string]func(context.Contex
 	}
 }
 
+// WithLoggerFieldExtractors 添加从 context 中提取类型化日志字段的提取器.
+func WithLoggerFieldExtractors(extractors ...log.FieldExtractor) Option {
+	return func(app *App) {
+		app.fieldExtractors = append(app.fieldExtractors, extractors...)
+	}
+}
+
 // NewApp creates a new application instance based on the given application name,
 // binary name, and other options.
 func NewApp(name string, shortDesc string, opts ...Option) *App {
@@ -291,7 +320,9 @@ func (app *App) runCommand(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	app.initializeLogger()
+	if err := app.initializeLogger(); err != nil {
+		return err
+	}
 
 	if !app.silence {
 		log.Infow("starting application", "name", app.name, "version", version.Get().ToJSON())
@@ -309,9 +340,36 @@ func (app *App) runCommand(cmd *cobra.Command, args []string) error {
 		var c *container.Container
 		c = container.New(app.name)
 
-		// 如果配置中包含ShutdownOverallTimeout，则设置容器选项
+		// 如果配置中包含启动和关闭相关的超时时间，则设置容器选项
 		if cfg, ok := app.options.(*config.Config); ok {
-			c = container.New(app.name, container.WithShutdownOverallTimeout(cfg.ShutdownOverallTimeout))
+			opts := []container.Option{
+				container.WithShutdownOverallTimeout(cfg.ShutdownOverallTimeout),
+				container.WithStartupTimeout(cfg.StartupTimeout),
+				container.WithStartTimeout(cfg.ComponentStartTimeout),
+				container.WithStopTimeout(cfg.ComponentStopTimeout),
+			}
+			// 设置组件的监管策略
+			for name, policy := range cfg.Supervision {
+				opts = append(opts, container.WithSupervision(name, container.SupervisionPolicy{
+					Restart:        container.RestartPolicy(policy.Restart),
+					MaxRetries:     policy.MaxRetries,
+					InitialBackoff: policy.InitialBackoff,
+					MaxBackoff:     policy.MaxBackoff,
+					Critical:       policy.Critical,
+				}))
+			}
+			// 记录组件状态、启停耗时和重启次数指标
+			if cfg.MetricsOptions.Enabled {
+				opts = append(opts, container.WithEventHandler(metrics.ContainerEventHandler))
+			}
+			c = container.New(app.name, opts...)
+		}
+		app.container = c
+
+		// 监听配置文件变化，重新加载配置并通知组件
+		if app.watch && !app.noConfig {
+			app.current = app.options
+			viper.OnConfigChange(app.onConfigChange)
 		}
 
 		return app.componentRunner.RunWithComponents(c)
@@ -344,7 +402,7 @@ func formatBaseName(name string) string {
 }
 
 // initializeLogger sets up the logging system based on the configuration.
-func (app *App) initializeLogger() {
+func (app *App) initializeLogger() error {
 	logOptions := log.NewOptions()
 
 	// Configure logging options from viper
@@ -363,7 +421,53 @@ func (app *App) initializeLogger() {
 	if viper.IsSet("log.output-paths") {
 		logOptions.OutputPaths = viper.GetStringSlice("log.output-paths")
 	}
+	if viper.IsSet("log.sinks") {
+		if err := viper.UnmarshalKey("log.sinks", &logOptions.Sinks); err != nil {
+			return fmt.Errorf("failed to parse log sinks: %w", err)
+		}
+	}
+	if viper.IsSet("log.rotate.max-size") {
+		logOptions.Rotate.MaxSize = viper.GetInt("log.rotate.max-size")
+	}
+	if viper.IsSet("log.rotate.max-age") {
+		logOptions.Rotate.MaxAge = viper.GetInt("log.rotate.max-age")
+	}
+	if viper.IsSet("log.rotate.max-backups") {
+		logOptions.Rotate.MaxBackups = viper.GetInt("log.rotate.max-backups")
+	}
+	if viper.IsSet("log.rotate.compress") {
+		logOptions.Rotate.Compress = viper.GetBool("log.rotate.compress")
+	}
+	if viper.IsSet("log.rotate.local-time") {
+		logOptions.Rotate.LocalTime = viper.GetBool("log.rotate.local-time")
+	}
+	if viper.IsSet("log.sampling.initial") {
+		logOptions.Sampling.Initial = viper.GetInt("log.sampling.initial")
+	}
+	if viper.IsSet("log.sampling.thereafter") {
+		logOptions.Sampling.Thereafter = viper.GetInt("log.sampling.thereafter")
+	}
+	if viper.IsSet("log.error-rate-limit") {
+		logOptions.ErrorRateLimit = viper.GetDuration("log.error-rate-limit")
+	}
+	if viper.IsSet("log.enable-trace-context") {
+		logOptions.EnableTraceContext = viper.GetBool("log.enable-trace-context")
+	}
+	if viper.IsSet("log.enable-debug-signal") {
+		logOptions.EnableDebugSignal = viper.GetBool("log.enable-debug-signal")
+	}
+	if viper.IsSet("log.debug-signal-ttl") {
+		logOptions.DebugSignalTTL = viper.GetDuration("log.debug-signal-ttl")
+	}
 
 	// Initialize logging with custom context extractors
-	log.Init(logOptions, log.WithContextExtractor(app.contextExtractors))
+	if err := log.Init(logOptions, log.WithContextExtractor(app.contextExtractors), log.WithFieldExtractors(app.fieldExtractors...)); err != nil {
+		return fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	// Toggle the debug level on SIGUSR1 for the lifetime of the process
+	if logOptions.EnableDebugSignal {
+		log.ToggleDebugOnSignal(logOptions.DebugSignalTTL)
+	}
+	return nil
 }
@@ -2,7 +2,9 @@ package app
 
 import (
 	"github.com/yanking/micro-zero/pkg/components/httpserver"
+	"github.com/yanking/micro-zero/pkg/components/mongo"
 	"github.com/yanking/micro-zero/pkg/components/mysql"
+	"github.com/yanking/micro-zero/pkg/components/postgresql"
 	"github.com/yanking/micro-zero/pkg/components/redis"
 	"github.com/yanking/micro-zero/pkg/config"
 	"github.com/yanking/micro-zero/pkg/contract"
@@ -29,6 +31,13 @@ func (d *defaultComponentsProvider) GetDefaultComponents() []contract.Component
 		components = append(components, mysqlComponent)
 	}
 
+	// 添加 PostgreSQL 组件（如果配置了地址）
+	if d.cfg.PostgreSQLOptions.Addr != "" {
+		if postgresqlComponent, err := postgresql.New(d.cfg.PostgreSQLOptions); err == nil {
+			components = append(components, postgresqlComponent)
+		}
+	}
+
 	// 添加 HTTP Server 组件（如果配置有效）
 	if httpComponent, err := httpserver.New(d.cfg.HTTPOptions); err == nil {
 		components = append(components, httpComponent)
@@ -39,5 +48,10 @@ func (d *defaultComponentsProvider) GetDefaultComponents() []contract.Component
 		components = append(components, redisComponent)
 	}
 
+	// 添加 MongoDB 组件（如果配置了 URL）
+	if d.cfg.MongoOptions.URL != "" {
+		components = append(components, mongo.New(d.cfg.MongoOptions))
+	}
+
 	return components
 }
@@ -1,53 +1,245 @@
 package app
 
 import (
-	"github.com/yanking/micro-zero/pkg/components/httpserver"
+	"fmt"
+	"io/fs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+
+	"github.com/yanking/micro-zero/internal/pkg/known"
+	"github.com/yanking/micro-zero/pkg/components/ginserver"
+	"github.com/yanking/micro-zero/pkg/components/grpcgateway"
+	"github.com/yanking/micro-zero/pkg/components/grpcserver"
+	"github.com/yanking/micro-zero/pkg/components/healthserver"
+	"github.com/yanking/micro-zero/pkg/components/metricsserver"
+	"github.com/yanking/micro-zero/pkg/components/mongo"
 	"github.com/yanking/micro-zero/pkg/components/mysql"
+	"github.com/yanking/micro-zero/pkg/components/postgresql"
 	"github.com/yanking/micro-zero/pkg/components/redis"
+	"github.com/yanking/micro-zero/pkg/components/tracing"
 	"github.com/yanking/micro-zero/pkg/config"
 	"github.com/yanking/micro-zero/pkg/container"
 	"github.com/yanking/micro-zero/pkg/log"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
 // defaultComponentRunner 是ComponentRunner接口的默认实现
 type defaultComponentRunner struct {
 	cfg *config.Config
+
+	grpcServices    []grpcserver.RegisterFunc
+	gatewayHandlers []grpcgateway.RegisterFunc
+	ginRoutes       []ginserver.RouteFunc
+	openAPI         fs.FS
+}
+
+// RunnerOption 定义了默认组件运行器的可选配置项.
+type RunnerOption func(*defaultComponentRunner)
+
+// WithGRPCServices 注册 gRPC 业务服务，在 grpc 和 grpc-gateway 模式下生效.
+func WithGRPCServices(registers ...grpcserver.RegisterFunc) RunnerOption {
+	return func(d *defaultComponentRunner) {
+		d.grpcServices = append(d.grpcServices, registers...)
+	}
+}
+
+// WithGatewayHandlers 注册生成的 gRPC-Gateway 反向代理处理器，在 grpc-gateway 模式下生效.
+func WithGatewayHandlers(registers ...grpcgateway.RegisterFunc) RunnerOption {
+	return func(d *defaultComponentRunner) {
+		d.gatewayHandlers = append(d.gatewayHandlers, registers...)
+	}
+}
+
+// WithGinRoutes 注册 Gin 业务路由，在 gin 模式下生效.
+func WithGinRoutes(routes ...ginserver.RouteFunc) RunnerOption {
+	return func(d *defaultComponentRunner) {
+		d.ginRoutes = append(d.ginRoutes, routes...)
+	}
+}
+
+// WithOpenAPI 设置 gRPC-Gateway 提供的 OpenAPI 文档，例如 os.DirFS("api/openapi").
+func WithOpenAPI(fsys fs.FS) RunnerOption {
+	return func(d *defaultComponentRunner) {
+		d.openAPI = fsys
+	}
 }
 
 // NewDefaultComponentRunner 创建一个新的默认组件运行器
-func NewDefaultComponentRunner(cfg *config.Config) ComponentRunner {
-	return &defaultComponentRunner{
+func NewDefaultComponentRunner(cfg *config.Config, opts ...RunnerOption) ComponentRunner {
+	d := &defaultComponentRunner{
 		cfg: cfg,
 	}
+	for _, o := range opts {
+		o(d)
+	}
+	return d
 }
 
 // RunWithComponents 使用组件容器运行应用
 func (d *defaultComponentRunner) RunWithComponents(c *container.Container) error {
 	log.Infof("Registering default components...")
 
+	// 注册健康检查服务组件，提供容器汇总的 /livez、/readyz 和 /healthz 接口，以及修改日志级别的 /debug/loglevel 接口
+	healthComponent, err := healthserver.New(d.cfg.HealthOptions, healthserver.WithHandlers(func(mux *http.ServeMux) {
+		c.InstallHealthz(mux, d.cfg.HealthOptions.HealthCheckPath)
+		mux.Handle("/debug/loglevel", log.LevelHandler())
+	}))
+	if err != nil {
+		return err
+	}
+	if err := c.Register(healthComponent); err != nil {
+		return err
+	}
+	log.Infof("Health Server component registered")
+
+	// 注册指标服务组件，并为服务组件添加请求指标
+	var (
+		ginMiddlewares     []gin.HandlerFunc
+		ginOptions         []ginserver.Option
+		unaryInterceptors  []grpc.UnaryServerInterceptor
+		streamInterceptors []grpc.StreamServerInterceptor
+		gatewayMuxOptions  []runtime.ServeMuxOption
+		gatewayDialOptions []grpc.DialOption
+	)
+	if d.cfg.MetricsOptions.Enabled {
+		metricsComponent, err := metricsserver.New(d.cfg.MetricsOptions)
+		if err != nil {
+			return err
+		}
+		if err := c.Register(metricsComponent); err != nil {
+			return err
+		}
+		ginMiddlewares = append(ginMiddlewares, ginserver.Metrics())
+		unaryInterceptors = append(unaryInterceptors, grpcserver.MetricsUnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, grpcserver.MetricsStreamInterceptor())
+		gatewayMuxOptions = append(gatewayMuxOptions, runtime.WithMiddlewares(grpcgateway.MetricsMiddleware()))
+		log.Infof("Metrics Server component registered")
+	}
+
+	// HTTP Server 依赖成功注册的存储组件和链路追踪组件，容器会据此决定启动和停止顺序
+	var dependencies []string
+
+	// 注册链路追踪组件，并为服务组件添加 span. 服务组件停止后才关闭 TracerProvider，保证导出所有的 span
+	if d.cfg.TracingOptions.Enabled {
+		tracingComponent, err := tracing.New(d.cfg.TracingOptions, tracing.WithServiceName(c.Name()))
+		if err != nil {
+			return err
+		}
+		if err := c.Register(tracingComponent); err != nil {
+			return err
+		}
+		dependencies = append(dependencies, tracingComponent.Name())
+		ginOptions = append(ginOptions, ginserver.WithTracing())
+		unaryInterceptors = append(unaryInterceptors, grpcserver.TracingUnaryInterceptor())
+		streamInterceptors = append(streamInterceptors, grpcserver.TracingStreamInterceptor())
+		gatewayMuxOptions = append(gatewayMuxOptions, runtime.WithMiddlewares(grpcgateway.TracingMiddleware()))
+		gatewayDialOptions = append(gatewayDialOptions, grpcgateway.TracingDialOptions()...)
+		log.Infof("Tracing component registered")
+	}
+
 	// 注册 MySQL 组件
 	if mysqlComponent, err := mysql.New(d.cfg.MySQLOptions); err == nil {
-		c.Register(mysqlComponent)
+		if err := c.Register(mysqlComponent); err != nil {
+			return err
+		}
+		dependencies = append(dependencies, mysqlComponent.Name())
 		log.Infof("MySQL component registered")
 	} else {
 		log.Warnf("Failed to create MySQL component: %v", err)
 	}
 
-	// 注册 HTTP Server 组件
-	if httpComponent, err := httpserver.New(d.cfg.HTTPOptions); err == nil {
-		c.Register(httpComponent)
-		log.Infof("HTTP Server component registered")
-	} else {
-		log.Warnf("Failed to create HTTP Server component: %v", err)
+	// 注册 PostgreSQL 组件（如果配置了地址）
+	if d.cfg.PostgreSQLOptions.Addr != "" {
+		if postgresqlComponent, err := postgresql.New(d.cfg.PostgreSQLOptions); err == nil {
+			if err := c.Register(postgresqlComponent); err != nil {
+				return err
+			}
+			dependencies = append(dependencies, postgresqlComponent.Name())
+			log.Infof("PostgreSQL component registered")
+		} else {
+			log.Warnf("Failed to create PostgreSQL component: %v", err)
+		}
 	}
 
-	// 注册 Redis 组件
+	// 注册 Redis 组件，连接在组件启动时建立，无法连接时按监管策略处理
 	if redisComponent, err := redis.New(d.cfg.RedisOptions); err == nil {
-		c.Register(redisComponent)
+		if err := c.Register(redisComponent); err != nil {
+			return err
+		}
+		dependencies = append(dependencies, redisComponent.Name())
 		log.Infof("Redis component registered")
 	} else {
 		log.Warnf("Failed to create Redis component: %v", err)
 	}
 
-	return c.Run()
+	// 注册 MongoDB 组件（如果配置了 URL），连接在组件启动时建立
+	if d.cfg.MongoOptions.URL != "" {
+		mongoComponent := mongo.New(d.cfg.MongoOptions)
+		if err := c.Register(mongoComponent); err != nil {
+			return err
+		}
+		dependencies = append(dependencies, mongoComponent.Name())
+		log.Infof("MongoDB component registered")
+	}
+
+	// 根据服务模式选择服务组件
+	switch d.cfg.ServerMode {
+	case known.GRPCServerMode, known.GRPCGatewayServerMode:
+		grpcComponent, err := grpcserver.New(d.cfg.GRPCOptions,
+			grpcserver.WithDependencies(dependencies...),
+			grpcserver.WithServices(d.grpcServices...),
+			grpcserver.WithUnaryInterceptors(unaryInterceptors...),
+			grpcserver.WithStreamInterceptors(streamInterceptors...),
+		)
+		if err != nil {
+			return err
+		}
+		if err := c.Register(grpcComponent); err != nil {
+			return err
+		}
+		log.Infof("gRPC Server component registered")
+
+		if d.cfg.ServerMode != known.GRPCGatewayServerMode {
+			break
+		}
+		// 网关依赖 gRPC 服务组件，两者由容器统一启动和停止
+		gatewayOptions := []grpcgateway.Option{
+			grpcgateway.WithHandlers(d.gatewayHandlers...),
+			grpcgateway.WithServeMuxOptions(gatewayMuxOptions...),
+			grpcgateway.WithDialOptions(gatewayDialOptions...),
+		}
+		if d.openAPI != nil {
+			gatewayOptions = append(gatewayOptions, grpcgateway.WithOpenAPI(d.openAPI))
+		}
+		gatewayComponent, err := grpcgateway.New(d.cfg.HTTPOptions, d.cfg.GRPCOptions, gatewayOptions...)
+		if err != nil {
+			return err
+		}
+		if err := c.Register(gatewayComponent); err != nil {
+			return err
+		}
+		log.Infof("gRPC Gateway component registered")
+	case known.GinServerMode:
+		ginComponent, err := ginserver.New(d.cfg.HTTPOptions, append(ginOptions,
+			ginserver.WithDependencies(dependencies...),
+			ginserver.WithRoutes(d.ginRoutes...),
+			ginserver.WithMiddlewares(ginMiddlewares...),
+		)...)
+		if err != nil {
+			return err
+		}
+		if err := c.Register(ginComponent); err != nil {
+			return err
+		}
+		log.Infof("Gin Server component registered")
+	default:
+		return fmt.Errorf("unsupported server mode: %s", d.cfg.ServerMode)
+	}
+
+	// 停止阶段各组件的错误记录在关闭报告中，与运行错误一起返回
+	report, err := c.Run()
+	return utilerrors.NewAggregate([]error{err, report.Err()})
 }
@@ -5,7 +5,6 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
@@ -55,11 +54,9 @@ func AddConfigFlag(fs *pflag.FlagSet, name string, watch bool) {
 		}
 		log.Debugw("Success to read configuration file", "file", viper.ConfigFileUsed())
 
+		// 配置文件变化时的处理逻辑由 App 通过 viper.OnConfigChange 注册
 		if watch {
 			viper.WatchConfig()
-			viper.OnConfigChange(func(e fsnotify.Event) {
-				log.Debugw("Config file changed", "name", e.Name)
-			})
 		}
 	})
 }
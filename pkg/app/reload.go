@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"go.uber.org/zap/zapcore"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	"github.com/yanking/micro-zero/pkg/config"
+	"github.com/yanking/micro-zero/pkg/log"
+)
+
+// reconfigureTimeout 是通知所有组件应用新配置的超时时间.
+const reconfigureTimeout = 10 * time.Second
+
+// onConfigChange 在配置文件变化时重新加载配置.
+func (app *App) onConfigChange(e fsnotify.Event) {
+	log.Infow("config file changed, reloading", "name", e.Name, "op", e.Op.String())
+	if err := app.reloadConfig(); err != nil {
+		log.Errorw(err, "failed to reload config, keeping the running config")
+	}
+}
+
+// reloadConfig 将配置重新读取到一份新的配置结构体中并校验，校验失败时继续使用原来的配置；
+// 校验通过后对比新旧配置，并通知实现了 config.Reconfigurable 的组件应用新配置.
+func (app *App) reloadConfig() error {
+	app.reloadMu.Lock()
+	defer app.reloadMu.Unlock()
+
+	running, ok := app.current.(*config.Config)
+	if !ok {
+		return errors.New("reloading is only supported for *config.Config options")
+	}
+
+	newCfg := config.New()
+	if err := viper.Unmarshal(newCfg); err != nil {
+		return fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+	if err := newCfg.Complete(); err != nil {
+		return fmt.Errorf("failed to complete config: %w", err)
+	}
+	if err := newCfg.Validate(); err != nil {
+		return fmt.Errorf("invalid config: %w", err)
+	}
+
+	changed, err := config.Diff(running, newCfg)
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		log.Infof("config reloaded, nothing changed")
+		return nil
+	}
+	log.Infow("config reloaded", "changed", changed)
+	if keys := config.RestartRequired(changed); len(keys) > 0 {
+		log.Warnw("some config changes take effect after restart", "keys", keys)
+	}
+
+	// 日志级别由 log 包统一管理，不需要组件参与
+	if newCfg.LogsOptions.Level != running.LogsOptions.Level {
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(newCfg.LogsOptions.Level)); err != nil {
+			return fmt.Errorf("invalid log level %q: %w", newCfg.LogsOptions.Level, err)
+		}
+		log.SetLevel(level)
+		log.Infow("log level changed", "level", level.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), reconfigureTimeout)
+	defer cancel()
+
+	var errs []error
+	if app.container != nil {
+		for _, cp := range app.container.Components() {
+			r, ok := cp.(config.Reconfigurable)
+			if !ok {
+				continue
+			}
+			if err := r.Reconfigure(ctx, newCfg); err != nil {
+				errs = append(errs, fmt.Errorf("component %s: %w", cp.Name(), err))
+				continue
+			}
+			log.Infow("component reconfigured", "component", cp.Name())
+		}
+	}
+
+	// 即使部分组件应用失败，新配置也已通过校验，以它作为后续对比的基准
+	app.current = newCfg
+	return utilerrors.NewAggregate(errs)
+}
@@ -0,0 +1,129 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/yanking/micro-zero/pkg/config"
+	"github.com/yanking/micro-zero/pkg/container"
+	"github.com/yanking/micro-zero/pkg/log"
+)
+
+// fakeComponent is a component that does not support reloading.
+type fakeComponent struct {
+	name string
+}
+
+func (c *fakeComponent) Start(context.Context) error { return nil }
+func (c *fakeComponent) Stop(context.Context) error  { return nil }
+func (c *fakeComponent) Name() string                { return c.name }
+
+// reconfigurableComponent records the configs it is reconfigured with.
+type reconfigurableComponent struct {
+	fakeComponent
+	configs []*config.Config
+}
+
+func (c *reconfigurableComponent) Reconfigure(_ context.Context, newCfg *config.Config) error {
+	c.configs = append(c.configs, newCfg)
+	return nil
+}
+
+// newReloadApp returns an app running cfg with the given components, whose
+// log records are returned by the returned function.
+func newReloadApp(t *testing.T, cfg *config.Config, components ...*reconfigurableComponent) (*App, func() []string) {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), "app.log")
+	opts := log.NewOptions()
+	opts.OutputPaths = []string{file}
+	require.NoError(t, log.Init(opts))
+	t.Cleanup(func() { log.Init(log.NewOptions()) })
+
+	c := container.New("test")
+	require.NoError(t, c.Register(&fakeComponent{name: "plain"}))
+	for _, cp := range components {
+		require.NoError(t, c.Register(cp))
+	}
+
+	lines := func() []string {
+		log.Sync()
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+	return &App{container: c, current: cfg}, lines
+}
+
+// loadConfig makes viper read the YAML config.
+func loadConfig(t *testing.T, yaml string) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	viper.SetConfigType("yaml")
+	require.NoError(t, viper.ReadConfig(strings.NewReader(yaml)))
+}
+
+func TestReloadConfigRejectsInvalidConfig(t *testing.T) {
+	running := config.New()
+	rc := &reconfigurableComponent{fakeComponent: fakeComponent{name: "redis-client"}}
+	app, _ := newReloadApp(t, running, rc)
+
+	loadConfig(t, "health:\n  check-path: /readyz\nredis:\n  pool-size: 20\n")
+	err := app.reloadConfig()
+	require.ErrorContains(t, err, "check-path")
+	assert.Same(t, running, app.current)
+	assert.Empty(t, rc.configs)
+}
+
+func TestReloadConfigWarnsAboutRestartRequiredKeys(t *testing.T) {
+	running := config.New()
+	rc := &reconfigurableComponent{fakeComponent: fakeComponent{name: "redis-client"}}
+	app, lines := newReloadApp(t, running, rc)
+
+	loadConfig(t, "http:\n  timeout: 1m\n")
+	require.NoError(t, app.reloadConfig())
+
+	var warnings []string
+	for _, line := range lines() {
+		if strings.Contains(line, "take effect after restart") {
+			warnings = append(warnings, line)
+		}
+	}
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "http.timeout")
+	// The new config is accepted, the change itself is not applied by the app.
+	assert.NotSame(t, running, app.current)
+	assert.Len(t, rc.configs, 1)
+}
+
+func TestReloadConfigAppliesLogLevel(t *testing.T) {
+	app, _ := newReloadApp(t, config.New())
+	log.SetLevel(zapcore.InfoLevel)
+
+	loadConfig(t, "log:\n  level: debug\n")
+	require.NoError(t, app.reloadConfig())
+	assert.Equal(t, zapcore.DebugLevel, log.Level())
+}
+
+func TestReloadConfigReconfiguresComponents(t *testing.T) {
+	rc := &reconfigurableComponent{fakeComponent: fakeComponent{name: "redis-client"}}
+	app, _ := newReloadApp(t, config.New(), rc)
+
+	loadConfig(t, "redis:\n  pool-size: 20\n")
+	require.NoError(t, app.reloadConfig())
+
+	// Only the component implementing config.Reconfigurable is called, the
+	// plain component registered by newReloadApp is skipped.
+	require.Len(t, rc.configs, 1)
+	assert.Equal(t, 20, rc.configs[0].RedisOptions.PoolSize)
+	assert.Same(t, rc.configs[0], app.current)
+}
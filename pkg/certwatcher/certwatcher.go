@@ -0,0 +1,290 @@
+// Package certwatcher keeps TLS certificates and CA bundles in sync with the
+// files on disk, so that certificates rotated by e.g. cert-manager are picked
+// up without restarting the process.
+package certwatcher
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/yanking/micro-zero/pkg/log"
+)
+
+const (
+	// debounce groups the burst of events caused by one rotation.
+	debounce = 100 * time.Millisecond
+	// expiryWarning is the remaining validity below which loading a
+	// certificate logs a warning.
+	expiryWarning = 7 * 24 * time.Hour
+)
+
+// CertWatcher serves the latest certificate and CA bundle read from disk.
+// If reading the files fails, the previously loaded ones are kept.
+type CertWatcher struct {
+	certFile string
+	keyFile  string
+	caFile   string
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	caPool   *x509.CertPool
+	notAfter time.Time
+}
+
+// Option configures a CertWatcher.
+type Option func(*CertWatcher)
+
+// WithCAFile additionally watches a PEM encoded CA bundle.
+func WithCAFile(caFile string) Option {
+	return func(w *CertWatcher) {
+		w.caFile = caFile
+	}
+}
+
+// New returns a CertWatcher for the given certificate and key files. The
+// files are read once; call Start to follow changes. Either certFile and
+// keyFile or the CA file may be empty.
+func New(certFile, keyFile string, opts ...Option) (*CertWatcher, error) {
+	w := &CertWatcher{certFile: certFile, keyFile: keyFile}
+	for _, o := range opts {
+		o(w)
+	}
+	if (w.certFile == "") != (w.keyFile == "") {
+		return nil, errors.New("certwatcher: both cert and key file must be set")
+	}
+
+	if err := w.Reload(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Reload reads the certificate and the CA bundle from disk. On error the
+// previously loaded ones stay in use.
+func (w *CertWatcher) Reload() error {
+	var (
+		cert     *tls.Certificate
+		notAfter time.Time
+		caPool   *x509.CertPool
+	)
+
+	if w.certFile != "" {
+		pair, err := tls.LoadX509KeyPair(w.certFile, w.keyFile)
+		if err != nil {
+			return fmt.Errorf("certwatcher: failed to load certificate %s: %w", w.certFile, err)
+		}
+		cert, notAfter = &pair, pair.Leaf.NotAfter
+	}
+	if w.caFile != "" {
+		data, err := os.ReadFile(w.caFile)
+		if err != nil {
+			return fmt.Errorf("certwatcher: failed to read ca bundle: %w", err)
+		}
+		caPool = x509.NewCertPool()
+		if !caPool.AppendCertsFromPEM(data) {
+			return fmt.Errorf("certwatcher: no certificates found in ca bundle %s", w.caFile)
+		}
+	}
+
+	w.mu.Lock()
+	w.cert, w.notAfter, w.caPool = cert, notAfter, caPool
+	w.mu.Unlock()
+
+	if cert != nil {
+		expiresIn := time.Until(notAfter).Round(time.Second)
+		if expiresIn < expiryWarning {
+			log.Warnw("certwatcher: certificate expires soon", "cert", w.certFile, "not_after", notAfter, "expires_in", expiresIn)
+		} else {
+			log.Infow("certwatcher: certificate loaded", "cert", w.certFile, "not_after", notAfter, "expires_in", expiresIn)
+		}
+	}
+	return nil
+}
+
+// Start watches the files for changes and reloads them until ctx is done.
+// The directories of the files are watched, so that the atomic symlink swaps
+// of Kubernetes secret volumes are noticed as well. While running, the
+// certificate expiry is reported by Expiries.
+func (w *CertWatcher) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("certwatcher: failed to create watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	if w.certFile != "" {
+		active.add(w)
+		defer active.remove(w)
+	}
+
+	dirs := make(map[string]struct{})
+	for _, file := range []string{w.certFile, w.keyFile, w.caFile} {
+		if file == "" {
+			continue
+		}
+		dir := filepath.Dir(file)
+		if _, ok := dirs[dir]; ok {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			return fmt.Errorf("certwatcher: failed to watch %s: %w", dir, err)
+		}
+		dirs[dir] = struct{}{}
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Has(fsnotify.Chmod) {
+				continue
+			}
+			timer.Reset(debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warnw("certwatcher: watch error", "err", err)
+		case <-timer.C:
+			if err := w.Reload(); err != nil {
+				log.Errorw(err, "certwatcher: reload failed, keeping the previous certificate")
+			}
+		}
+	}
+}
+
+// GetCertificate returns the current certificate. It is meant for
+// tls.Config.GetCertificate.
+func (w *CertWatcher) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return w.certificate()
+}
+
+// GetClientCertificate returns the current certificate. It is meant for
+// tls.Config.GetClientCertificate.
+func (w *CertWatcher) GetClientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return w.certificate()
+}
+
+func (w *CertWatcher) certificate() (*tls.Certificate, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.cert == nil {
+		return nil, errors.New("certwatcher: no certificate configured")
+	}
+	return w.cert, nil
+}
+
+// CAPool returns the current CA pool, or nil if no CA file is watched.
+func (w *CertWatcher) CAPool() *x509.CertPool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.caPool
+}
+
+// NotAfter returns the expiry of the current certificate.
+func (w *CertWatcher) NotAfter() time.Time {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.notAfter
+}
+
+// ServerConfig returns a copy of base serving the current certificate and
+// verifying clients against the current CA pool.
+func (w *CertWatcher) ServerConfig(base *tls.Config) *tls.Config {
+	cfg := &tls.Config{}
+	if base != nil {
+		cfg = base.Clone()
+	}
+	cfg.Certificates = nil
+	cfg.GetCertificate = w.GetCertificate
+	if w.caFile != "" {
+		cfg.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			c := cfg.Clone()
+			c.GetConfigForClient = nil
+			c.ClientCAs = w.CAPool()
+			return c, nil
+		}
+	}
+	return cfg
+}
+
+// ClientConfig returns a copy of base presenting the current certificate and
+// verifying servers against the current CA pool.
+func (w *CertWatcher) ClientConfig(base *tls.Config) *tls.Config {
+	cfg := &tls.Config{}
+	if base != nil {
+		cfg = base.Clone()
+	}
+	if w.certFile != "" {
+		cfg.Certificates = nil
+		cfg.GetClientCertificate = w.GetClientCertificate
+	}
+	if w.caFile != "" && !cfg.InsecureSkipVerify {
+		// RootCAs cannot change after the config is in use, so the chain is
+		// verified against the current pool by hand.
+		cfg.InsecureSkipVerify = true //nolint:gosec // verified in VerifyConnection.
+		cfg.VerifyConnection = func(cs tls.ConnectionState) error {
+			opts := x509.VerifyOptions{
+				DNSName:       cs.ServerName,
+				Roots:         w.CAPool(),
+				Intermediates: x509.NewCertPool(),
+			}
+			for _, cert := range cs.PeerCertificates[1:] {
+				opts.Intermediates.AddCert(cert)
+			}
+			_, err := cs.PeerCertificates[0].Verify(opts)
+			return err
+		}
+	}
+	return cfg
+}
+
+// registry tracks the running watchers.
+type registry struct {
+	mu       sync.Mutex
+	watchers map[*CertWatcher]struct{}
+}
+
+var active = &registry{watchers: make(map[*CertWatcher]struct{})}
+
+func (r *registry) add(w *CertWatcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.watchers[w] = struct{}{}
+}
+
+func (r *registry) remove(w *CertWatcher) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.watchers, w)
+}
+
+// Expiries returns the expiry of the certificates of all running watchers,
+// keyed by certificate file.
+func Expiries() map[string]time.Time {
+	active.mu.Lock()
+	defer active.mu.Unlock()
+
+	expiries := make(map[string]time.Time, len(active.watchers))
+	for w := range active.watchers {
+		expiries[w.certFile] = w.NotAfter()
+	}
+	return expiries
+}
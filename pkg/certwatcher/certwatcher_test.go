@@ -0,0 +1,76 @@
+package certwatcher
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeCert writes a self-signed certificate valid for validFor and its key.
+func writeCert(t *testing.T, certFile, keyFile string, validFor time.Duration) time.Time {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	notAfter := time.Now().Add(validFor).Truncate(time.Second).UTC()
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	return notAfter
+}
+
+func TestCertWatcherReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	first := writeCert(t, certFile, keyFile, 24*time.Hour)
+
+	w, err := New(certFile, keyFile, WithCAFile(certFile))
+	require.NoError(t, err)
+	assert.Equal(t, first, w.NotAfter())
+	assert.NotNil(t, w.CAPool())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = w.Start(ctx) }()
+	require.Eventually(t, func() bool { _, ok := Expiries()[certFile]; return ok }, 5*time.Second, 10*time.Millisecond)
+
+	// 证书轮转后使用新的证书
+	second := writeCert(t, certFile, keyFile, 48*time.Hour)
+	require.Eventually(t, func() bool { return w.NotAfter().Equal(second) }, 5*time.Second, 10*time.Millisecond)
+	cert, err := w.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, second, cert.Leaf.NotAfter)
+
+	// 无法解析的证书不会替换当前证书
+	require.NoError(t, os.WriteFile(certFile, []byte("garbage"), 0o600))
+	require.Error(t, w.Reload())
+	assert.Equal(t, second, w.NotAfter())
+	cert, err = w.GetClientCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, second, cert.Leaf.NotAfter)
+
+	cancel()
+	require.Eventually(t, func() bool { _, ok := Expiries()[certFile]; return !ok }, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,235 @@
+package ginserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/yanking/micro-zero/pkg/contract"
+	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+// ComponentName 是 Gin 服务组件的名称，可用于声明组件依赖.
+const ComponentName = "gin-server"
+
+var (
+	_ contract.Component          = (*Server)(nil)
+	_ contract.DependentComponent = (*Server)(nil)
+	_ contract.ReadinessChecker   = (*Server)(nil)
+	_ contract.ExitNotifier       = (*Server)(nil)
+)
+
+// RouteFunc 用于在 Gin 引擎上注册业务路由.
+type RouteFunc func(r *gin.Engine)
+
+// Server 实现了 Component 接口的 Gin HTTP 服务组件.
+// 内置 recovery、链路追踪、request-ID、访问日志和 CORS 中间件.
+type Server struct {
+	opts         *options.HTTPOptions
+	engine       *gin.Engine
+	dependencies []string
+	routes       []RouteFunc
+	middlewares  []gin.HandlerFunc
+	cors         CORSConfig
+	tracing      bool
+
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	server   *http.Server
+	listener net.Listener
+	serveErr error
+	// exited 在本次运行的服务异常退出时接收错误
+	exited chan error
+}
+
+// Option 定义了 Gin 服务组件的可选配置项.
+type Option func(*Server)
+
+// WithDependencies 声明 Gin 服务组件依赖的组件，容器会在这些组件启动之后再启动 Gin 服务.
+func WithDependencies(names ...string) Option {
+	return func(s *Server) {
+		s.dependencies = append(s.dependencies, names...)
+	}
+}
+
+// WithRoutes 注册业务路由.
+func WithRoutes(routes ...RouteFunc) Option {
+	return func(s *Server) {
+		s.routes = append(s.routes, routes...)
+	}
+}
+
+// WithMiddlewares 追加业务中间件，它们在内置中间件之后执行.
+func WithMiddlewares(middlewares ...gin.HandlerFunc) Option {
+	return func(s *Server) {
+		s.middlewares = append(s.middlewares, middlewares...)
+	}
+}
+
+// WithTracing 为每个请求创建服务端 span. 链路追踪中间件在访问日志之前执行，
+// 访问日志和业务中间件中使用 log.W(c.Request.Context()) 打印的日志都会带有链路信息.
+func WithTracing() Option {
+	return func(s *Server) {
+		s.tracing = true
+	}
+}
+
+// WithCORS 设置 CORS 配置，默认允许所有来源.
+func WithCORS(config CORSConfig) Option {
+	return func(s *Server) {
+		s.cors = config
+	}
+}
+
+// New 创建一个新的 Gin 服务组件实例
+func New(opts *options.HTTPOptions, serverOptions ...Option) (*Server, error) {
+	if opts == nil {
+		return nil, errors.New("http options must not be nil")
+	}
+
+	s := &Server{
+		opts: opts,
+		cors: DefaultCORSConfig(),
+	}
+	for _, o := range serverOptions {
+		o(s)
+	}
+
+	// Gin 默认以调试模式运行并打印路由等调试信息，这里默认使用发布模式，需要调试模式时设置环境变量 GIN_MODE=debug
+	if _, ok := os.LookupEnv(gin.EnvGinMode); !ok && gin.Mode() == gin.DebugMode {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	engine := gin.New()
+	engine.Use(Recovery())
+	if s.tracing {
+		engine.Use(Tracing())
+	}
+	engine.Use(RequestID(), AccessLog(), CORS(s.cors))
+	engine.Use(s.middlewares...)
+	for _, route := range s.routes {
+		route(engine)
+	}
+	s.engine = engine
+
+	return s, nil
+}
+
+// Start 启动 Gin 服务组件.
+// 端口在 Start 中同步绑定，绑定失败会直接返回错误；请求的处理在后台 goroutine 中进行.
+func (s *Server) Start(ctx context.Context) error {
+	log.Infof("component: Gin server starting on %s", s.opts.Addr)
+
+	// 证书的热加载随本次运行结束
+	runCtx, cancel := context.WithCancel(ctx)
+	tlsConfig, err := s.opts.TLSOptions.ServerTLSConfig(runCtx)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	ln, err := net.Listen(s.opts.Network, s.opts.Addr)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to listen on %s: %w", s.opts.Addr, err)
+	}
+
+	// http.Server 在 Shutdown 之后不能再次使用，每次启动都创建新的实例
+	server := &http.Server{
+		Handler:      s.engine,
+		TLSConfig:    tlsConfig,
+		ReadTimeout:  s.opts.Timeout,
+		WriteTimeout: s.opts.Timeout,
+		IdleTimeout:  s.opts.Timeout,
+	}
+
+	exited := make(chan error, 1)
+	s.mu.Lock()
+	s.cancel = cancel
+	s.server, s.listener, s.serveErr, s.exited = server, ln, nil, exited
+	s.mu.Unlock()
+
+	// 在单独的 goroutine 中启动服务器，以避免阻塞
+	go func() {
+		var err error
+		if tlsConfig != nil {
+			err = server.ServeTLS(ln, "", "")
+		} else {
+			err = server.Serve(ln)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("component: Gin server error: %v", err)
+			s.mu.Lock()
+			s.serveErr = err
+			s.mu.Unlock()
+			exited <- err
+		}
+	}()
+
+	return nil
+}
+
+// Ready 在端口绑定成功且服务没有异常退出时返回 nil
+func (s *Server) Ready(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.serveErr
+}
+
+// Exited 返回本次运行的 Gin 服务异常退出时接收错误的 channel，供容器按监管策略重启组件.
+// 调用 Stop 正常停止时不会发送.
+func (s *Server) Exited() <-chan error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exited
+}
+
+// Stop 停止 Gin 服务组件
+func (s *Server) Stop(ctx context.Context) error {
+	log.Infof("component: Stopping Gin server...")
+
+	s.mu.Lock()
+	server, cancel := s.server, s.cancel
+	s.mu.Unlock()
+	if server == nil {
+		return nil
+	}
+	defer cancel()
+	return server.Shutdown(ctx)
+}
+
+// Name 返回组件名称
+func (s *Server) Name() string {
+	return ComponentName
+}
+
+// DependsOn 返回 Gin 服务组件依赖的组件名称
+func (s *Server) DependsOn() []string {
+	return s.dependencies
+}
+
+// Engine 返回 Gin 引擎，可用于在 Start 之前注册额外的路由.
+func (s *Server) Engine() *gin.Engine {
+	return s.engine
+}
+
+// Addr 返回 Gin 服务实际监听的地址，在 Start 之前返回 nil.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
@@ -0,0 +1,125 @@
+package ginserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+func newTestServer(t *testing.T, opts ...Option) *Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	routes := func(r *gin.Engine) {
+		r.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, GetRequestID(c)) })
+		r.GET("/panic", func(c *gin.Context) { panic("boom") })
+	}
+	s, err := New(options.NewHTTPOptions(), append([]Option{WithRoutes(routes)}, opts...)...)
+	require.NoError(t, err)
+	return s
+}
+
+func TestRequestID(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.Engine().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEmpty(t, rec.Body.String())
+	assert.Equal(t, rec.Body.String(), rec.Header().Get(RequestIDHeader))
+
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(RequestIDHeader, "abc")
+	rec = httptest.NewRecorder()
+	s.Engine().ServeHTTP(rec, req)
+	assert.Equal(t, "abc", rec.Body.String())
+}
+
+func TestRecovery(t *testing.T) {
+	s := newTestServer(t)
+
+	rec := httptest.NewRecorder()
+	s.Engine().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestCORS(t *testing.T) {
+	s := newTestServer(t, WithCORS(CORSConfig{
+		AllowOrigins: []string{"https://example.com"},
+		AllowMethods: []string{http.MethodGet},
+		MaxAge:       time.Minute,
+	}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/ping", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	rec := httptest.NewRecorder()
+	s.Engine().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://example.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET", rec.Header().Get("Access-Control-Allow-Methods"))
+	assert.Equal(t, "60", rec.Header().Get("Access-Control-Max-Age"))
+
+	req = httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec = httptest.NewRecorder()
+	s.Engine().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestAccessLogHasTraceContext(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder)))
+
+	file := filepath.Join(t.TempDir(), "app.log")
+	opts := log.NewOptions()
+	opts.Format = "json"
+	opts.OutputPaths = []string{file}
+	opts.EnableTraceContext = true
+	require.NoError(t, log.Init(opts))
+	t.Cleanup(func() { log.Init(log.NewOptions()) })
+
+	s := newTestServer(t, WithTracing())
+	rec := httptest.NewRecorder()
+	s.Engine().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	log.Sync()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"message":"http request"`)
+	assert.Contains(t, string(data), `"trace_id":"`+spans[0].SpanContext().TraceID().String()+`"`)
+}
+
+func TestReleaseModeByDefault(t *testing.T) {
+	if _, ok := os.LookupEnv(gin.EnvGinMode); ok {
+		t.Skipf("%s is set", gin.EnvGinMode)
+	}
+	gin.SetMode(gin.DebugMode)
+	t.Cleanup(func() { gin.SetMode(gin.TestMode) })
+
+	_, err := New(options.NewHTTPOptions())
+	require.NoError(t, err)
+	assert.Equal(t, gin.ReleaseMode, gin.Mode())
+
+	// 显式指定的模式保持不变
+	gin.SetMode(gin.TestMode)
+	_, err = New(options.NewHTTPOptions())
+	require.NoError(t, err)
+	assert.Equal(t, gin.TestMode, gin.Mode())
+}
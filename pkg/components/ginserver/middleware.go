@@ -0,0 +1,178 @@
+package ginserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/metrics"
+	"github.com/yanking/micro-zero/pkg/tracing"
+)
+
+// RequestIDHeader 是携带请求 ID 的 HTTP 头.
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey 是请求 ID 在 gin.Context 中的键.
+const requestIDKey = "request-id"
+
+// RequestID 返回一个中间件，它沿用请求头中的请求 ID，没有时生成一个新的，
+// 并将请求 ID 写入响应头和 gin.Context.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" {
+			id = uuid.NewString()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// GetRequestID 返回 RequestID 中间件设置的请求 ID.
+func GetRequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// AccessLog 返回一个记录访问日志的中间件，日志带有请求 context 中的链路信息.
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		begin := time.Now()
+		path := c.Request.URL.Path
+		if raw := c.Request.URL.RawQuery; raw != "" {
+			path = path + "?" + raw
+		}
+
+		c.Next()
+
+		keyvals := []any{
+			"method", c.Request.Method,
+			"path", path,
+			"route", c.FullPath(),
+			"status", c.Writer.Status(),
+			"latency", time.Since(begin),
+			"client_ip", c.ClientIP(),
+			"size", c.Writer.Size(),
+			"request_id", GetRequestID(c),
+		}
+		if len(c.Errors) > 0 {
+			keyvals = append(keyvals, "errors", c.Errors.String())
+		}
+		log.W(c.Request.Context()).Infow("http request", keyvals...)
+	}
+}
+
+// Metrics 返回一个记录请求数和请求耗时指标的中间件，指标按匹配的路由统计.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		begin := time.Now()
+		c.Next()
+		metrics.ObserveHTTPRequest(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(begin))
+	}
+}
+
+// Tracing 返回一个为每个请求创建服务端 span 的中间件，它沿用请求头中传递的链路，
+// span 按匹配的路由命名，例如 "GET /v1/users/:id". 处理器通过 c.Request.Context() 获取 span.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, span := tracing.StartHTTPServerSpan(c.Request)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+		tracing.EndHTTPServerSpan(span, c.Request.Method, c.FullPath(), c.Writer.Status())
+	}
+}
+
+// CORSConfig 定义跨域资源共享（CORS）中间件的配置.
+type CORSConfig struct {
+	// AllowOrigins 是允许的来源列表，"*" 表示允许所有来源.
+	AllowOrigins []string
+	// AllowMethods 是允许的请求方法.
+	AllowMethods []string
+	// AllowHeaders 是允许的请求头.
+	AllowHeaders []string
+	// ExposeHeaders 是允许浏览器读取的响应头.
+	ExposeHeaders []string
+	// AllowCredentials 表示是否允许携带凭证.
+	AllowCredentials bool
+	// MaxAge 是预检请求结果的缓存时间.
+	MaxAge time.Duration
+}
+
+// DefaultCORSConfig 返回默认的 CORS 配置：允许所有来源，并暴露请求 ID 响应头.
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowOrigins:  []string{"*"},
+		AllowMethods:  []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodHead, http.MethodOptions},
+		AllowHeaders:  []string{"Origin", "Content-Type", "Content-Length", "Accept", "Authorization", RequestIDHeader},
+		ExposeHeaders: []string{RequestIDHeader},
+		MaxAge:        12 * time.Hour,
+	}
+}
+
+// CORS 返回一个处理跨域请求的中间件，预检请求直接返回 204.
+func CORS(config CORSConfig) gin.HandlerFunc {
+	allowAll := slices.Contains(config.AllowOrigins, "*")
+	allowMethods := strings.Join(config.AllowMethods, ",")
+	allowHeaders := strings.Join(config.AllowHeaders, ",")
+	exposeHeaders := strings.Join(config.ExposeHeaders, ",")
+	maxAge := strconv.Itoa(int(config.MaxAge / time.Second))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		if !allowAll && !slices.Contains(config.AllowOrigins, origin) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		header := c.Writer.Header()
+		if allowAll && !config.AllowCredentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Add("Vary", "Origin")
+		}
+		if config.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+		if exposeHeaders != "" {
+			header.Set("Access-Control-Expose-Headers", exposeHeaders)
+		}
+
+		if c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != "" {
+			header.Set("Access-Control-Allow-Methods", allowMethods)
+			header.Set("Access-Control-Allow-Headers", allowHeaders)
+			if config.MaxAge > 0 {
+				header.Set("Access-Control-Max-Age", maxAge)
+			}
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Recovery 返回一个中间件，它从 panic 中恢复、记录错误和调用栈并返回 500.
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered any) {
+		log.Errorw(fmt.Errorf("%v", recovered), "panic recovered",
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"request_id", GetRequestID(c),
+			"stack", string(debug.Stack()),
+		)
+		c.AbortWithStatus(http.StatusInternalServerError)
+	})
+}
@@ -0,0 +1,328 @@
+package grpcgateway
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/yanking/micro-zero/pkg/certwatcher"
+	"github.com/yanking/micro-zero/pkg/components/grpcserver"
+	"github.com/yanking/micro-zero/pkg/contract"
+	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+// ComponentName 是 gRPC-Gateway 组件的名称，可用于声明组件依赖.
+const ComponentName = "grpc-gateway"
+
+// OpenAPIPath 是 OpenAPI 文档的访问路径前缀.
+const OpenAPIPath = "/openapi/"
+
+var (
+	_ contract.Component          = (*Gateway)(nil)
+	_ contract.DependentComponent = (*Gateway)(nil)
+	_ contract.ReadinessChecker   = (*Gateway)(nil)
+	_ contract.ExitNotifier       = (*Gateway)(nil)
+)
+
+// RegisterFunc 用于向网关注册生成的反向代理处理器，例如 pb.RegisterUserServiceHandler.
+// ctx 在网关停止时结束.
+type RegisterFunc func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error
+
+// Gateway 是将 HTTP/JSON 请求转换为 gRPC 请求的反向代理组件.
+// 它依赖进程内的 gRPC 服务组件，容器会在 gRPC 服务就绪之后启动网关，并在 gRPC 服务停止之前停止网关.
+type Gateway struct {
+	httpOpts     *options.HTTPOptions
+	grpcOpts     *options.GRPCOptions
+	dependencies []string
+	registers    []RegisterFunc
+	muxOpts      []runtime.ServeMuxOption
+	dialOpts     []grpc.DialOption
+	openAPI      fs.FS
+	serverName   string
+
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	server   *http.Server
+	conn     *grpc.ClientConn
+	listener net.Listener
+	serveErr error
+	// exited 在本次运行的服务异常退出时接收错误
+	exited chan error
+}
+
+// Option 定义了 gRPC-Gateway 组件的可选配置项.
+type Option func(*Gateway)
+
+// WithDependencies 追加网关依赖的组件，网关默认依赖 gRPC 服务组件.
+func WithDependencies(names ...string) Option {
+	return func(g *Gateway) {
+		g.dependencies = append(g.dependencies, names...)
+	}
+}
+
+// WithHandlers 注册生成的反向代理处理器，注册函数在每次启动网关时调用.
+func WithHandlers(registers ...RegisterFunc) Option {
+	return func(g *Gateway) {
+		g.registers = append(g.registers, registers...)
+	}
+}
+
+// WithServeMuxOptions 追加创建 runtime.ServeMux 时使用的选项.
+func WithServeMuxOptions(muxOpts ...runtime.ServeMuxOption) Option {
+	return func(g *Gateway) {
+		g.muxOpts = append(g.muxOpts, muxOpts...)
+	}
+}
+
+// WithDialOptions 追加连接 gRPC 服务时使用的选项，可以覆盖默认的传输凭证.
+func WithDialOptions(dialOpts ...grpc.DialOption) Option {
+	return func(g *Gateway) {
+		g.dialOpts = append(g.dialOpts, dialOpts...)
+	}
+}
+
+// WithOpenAPI 在 OpenAPIPath 下提供 protoc-gen-openapiv2 生成的 OpenAPI 文档.
+func WithOpenAPI(fsys fs.FS) Option {
+	return func(g *Gateway) {
+		g.openAPI = fsys
+	}
+}
+
+// WithServerName 设置校验 gRPC 服务证书时使用的主机名，
+// 默认使用服务端证书中的第一个 DNS 名称，没有 DNS 名称时使用第一个 IP 地址.
+func WithServerName(name string) Option {
+	return func(g *Gateway) {
+		g.serverName = name
+	}
+}
+
+// New 创建一个新的 gRPC-Gateway 组件实例
+func New(httpOpts *options.HTTPOptions, grpcOpts *options.GRPCOptions, gatewayOptions ...Option) (*Gateway, error) {
+	if httpOpts == nil || grpcOpts == nil {
+		return nil, errors.New("http and grpc options must not be nil")
+	}
+
+	g := &Gateway{
+		httpOpts:     httpOpts,
+		grpcOpts:     grpcOpts,
+		dependencies: []string{grpcserver.ComponentName},
+	}
+	for _, o := range gatewayOptions {
+		o(g)
+	}
+
+	return g, nil
+}
+
+// Start 连接 gRPC 服务、注册反向代理处理器并启动 HTTP 服务.
+// 端口在 Start 中同步绑定，绑定失败会直接返回错误；请求的处理在后台 goroutine 中进行.
+func (g *Gateway) Start(ctx context.Context) error {
+	target := dialTarget(g.grpcOpts.Addr)
+	log.Infof("component: gRPC gateway starting on %s, proxying to %s", g.httpOpts.Addr, target)
+
+	// 证书的热加载随本次运行结束
+	runCtx, cancel := context.WithCancel(ctx)
+	tlsConfig, err := g.httpOpts.TLSOptions.ServerTLSConfig(runCtx)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	dialOpts, err := g.dialOptions(runCtx)
+	if err != nil {
+		cancel()
+		return err
+	}
+	conn, err := grpc.NewClient(target, dialOpts...)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to create grpc client for %s: %w", target, err)
+	}
+
+	gwmux := runtime.NewServeMux(g.muxOpts...)
+	for _, register := range g.registers {
+		// 处理器启动的 goroutine 随网关停止而结束
+		if err := register(runCtx, gwmux, conn); err != nil {
+			_ = conn.Close()
+			cancel()
+			return fmt.Errorf("failed to register gateway handler: %w", err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/", gwmux)
+	if g.openAPI != nil {
+		mux.Handle(OpenAPIPath, http.StripPrefix(OpenAPIPath, http.FileServerFS(g.openAPI)))
+	}
+
+	ln, err := net.Listen(g.httpOpts.Network, g.httpOpts.Addr)
+	if err != nil {
+		_ = conn.Close()
+		cancel()
+		return fmt.Errorf("failed to listen on %s: %w", g.httpOpts.Addr, err)
+	}
+
+	server := &http.Server{Handler: mux, TLSConfig: tlsConfig}
+
+	exited := make(chan error, 1)
+	g.mu.Lock()
+	g.cancel = cancel
+	g.server, g.conn, g.listener, g.serveErr, g.exited = server, conn, ln, nil, exited
+	g.mu.Unlock()
+
+	// 在单独的 goroutine 中启动服务器，以避免阻塞
+	go func() {
+		var err error
+		if tlsConfig != nil {
+			err = server.ServeTLS(ln, "", "")
+		} else {
+			err = server.Serve(ln)
+		}
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("component: gRPC gateway error: %v", err)
+			g.mu.Lock()
+			g.serveErr = err
+			g.mu.Unlock()
+			exited <- err
+		}
+	}()
+
+	return nil
+}
+
+// Ready 在端口绑定成功且服务没有异常退出时返回 nil
+func (g *Gateway) Ready(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.serveErr
+}
+
+// Exited 返回本次运行的网关异常退出时接收错误的 channel，供容器按监管策略重启组件.
+// 调用 Stop 正常停止时不会发送.
+func (g *Gateway) Exited() <-chan error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.exited
+}
+
+// Stop 停止 HTTP 服务并关闭到 gRPC 服务的连接
+func (g *Gateway) Stop(ctx context.Context) error {
+	log.Infof("component: Stopping gRPC gateway...")
+
+	g.mu.Lock()
+	server, conn, cancel := g.server, g.conn, g.cancel
+	g.mu.Unlock()
+	if server == nil {
+		return nil
+	}
+	defer cancel()
+
+	err := server.Shutdown(ctx)
+	return errors.Join(err, conn.Close())
+}
+
+// Name 返回组件名称
+func (g *Gateway) Name() string {
+	return ComponentName
+}
+
+// DependsOn 返回网关依赖的组件名称
+func (g *Gateway) DependsOn() []string {
+	return g.dependencies
+}
+
+// Addr 返回网关实际监听的地址，在 Start 之前返回 nil.
+func (g *Gateway) Addr() net.Addr {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.listener == nil {
+		return nil
+	}
+	return g.listener.Addr()
+}
+
+// dialOptions 返回连接 gRPC 服务时使用的选项.
+// gRPC 服务开启 TLS 时，网关出示服务端证书以便通过双向认证，证书在 ctx 结束前随文件更新而重新加载.
+// 网关只连接本进程的服务，因此要求对端出示的正是本进程当前的服务端证书，并按 serverName 校验主机名.
+// client-ca 用于认证客户端，不用于认证服务端.
+func (g *Gateway) dialOptions(ctx context.Context) ([]grpc.DialOption, error) {
+	creds := insecure.NewCredentials()
+	if tlsOpts := g.grpcOpts.TLSOptions; tlsOpts != nil && tlsOpts.Enabled {
+		watcher, err := certwatcher.New(tlsOpts.Cert, tlsOpts.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load grpc tls certificate: %w", err)
+		}
+		go func() {
+			if err := watcher.Start(ctx); err != nil {
+				log.Errorw(err, "failed to watch grpc tls certificate, certificate rotation is disabled", "cert", tlsOpts.Cert)
+			}
+		}()
+		creds = credentials.NewTLS(&tls.Config{
+			GetClientCertificate: watcher.GetClientCertificate,
+			// 证书会被重新加载，不能使用固定的 RootCAs，服务端证书在 VerifyConnection 中校验
+			InsecureSkipVerify: true, //nolint:gosec // verified in VerifyConnection.
+			VerifyConnection: func(cs tls.ConnectionState) error {
+				return verifyServer(watcher, g.serverName, cs)
+			},
+		})
+	}
+	return append([]grpc.DialOption{grpc.WithTransportCredentials(creds)}, g.dialOpts...), nil
+}
+
+// verifyServer 校验 gRPC 服务出示的证书是本进程当前的服务端证书，且对 serverName 有效.
+// serverName 为空时使用证书中的第一个 DNS 名称或 IP 地址.
+func verifyServer(watcher *certwatcher.CertWatcher, serverName string, cs tls.ConnectionState) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("grpc server presented no certificate")
+	}
+
+	cert, err := watcher.GetClientCertificate(nil)
+	if err != nil {
+		return err
+	}
+	peer := cs.PeerCertificates[0]
+	if !bytes.Equal(peer.Raw, cert.Certificate[0]) {
+		return errors.New("grpc server presented a certificate other than the configured server certificate")
+	}
+
+	if serverName == "" {
+		switch {
+		case len(peer.DNSNames) > 0:
+			serverName = peer.DNSNames[0]
+		case len(peer.IPAddresses) > 0:
+			serverName = peer.IPAddresses[0].String()
+		default:
+			return errors.New("grpc server certificate has no DNS name or IP address to verify, set the server name of the gateway")
+		}
+	}
+	return peer.VerifyHostname(serverName)
+}
+
+// dialTarget 将 gRPC 服务的监听地址转换为进程内可以连接的地址，
+// 监听所有地址（例如 ":6666" 或 "0.0.0.0:6666"）时连接本机回环地址.
+func dialTarget(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -0,0 +1,81 @@
+package grpcgateway
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/yanking/micro-zero/pkg/components/grpcserver"
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+func TestDialTarget(t *testing.T) {
+	assert.Equal(t, "127.0.0.1:6666", dialTarget(":6666"))
+	assert.Equal(t, "127.0.0.1:6666", dialTarget("0.0.0.0:6666"))
+	assert.Equal(t, "10.0.0.1:6666", dialTarget("10.0.0.1:6666"))
+}
+
+func TestGatewayProxiesToGRPCServer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	grpcOpts := options.NewGRPCOptions()
+	grpcOpts.Addr = "127.0.0.1:0"
+	grpcServer, err := grpcserver.New(grpcOpts)
+	require.NoError(t, err)
+	require.NoError(t, grpcServer.Start(ctx))
+	defer grpcServer.Stop(ctx)
+	require.NoError(t, grpcServer.Ready(ctx))
+
+	httpOpts := options.NewHTTPOptions()
+	httpOpts.Addr = "127.0.0.1:0"
+	gatewayGRPCOpts := options.NewGRPCOptions()
+	gatewayGRPCOpts.Addr = grpcServer.Addr().String()
+
+	// 使用 gRPC 健康检查服务模拟生成的反向代理处理器
+	register := func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+		client := healthpb.NewHealthClient(conn)
+		return mux.HandlePath(http.MethodGet, "/v1/health", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+			resp, err := client.Check(r.Context(), &healthpb.HealthCheckRequest{})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			_, _ = io.WriteString(w, resp.GetStatus().String())
+		})
+	}
+
+	gw, err := New(httpOpts, gatewayGRPCOpts,
+		WithHandlers(register),
+		WithOpenAPI(fstest.MapFS{"apiserver.swagger.json": {Data: []byte(`{"swagger":"2.0"}`)}}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{grpcserver.ComponentName}, gw.DependsOn())
+	require.NoError(t, gw.Start(ctx))
+	defer gw.Stop(ctx)
+	require.NoError(t, gw.Ready(ctx))
+
+	base := "http://" + gw.Addr().String()
+	assert.Equal(t, "SERVING", get(t, base+"/v1/health"))
+	assert.Equal(t, `{"swagger":"2.0"}`, get(t, base+OpenAPIPath+"apiserver.swagger.json"))
+}
+
+func get(t *testing.T, url string) string {
+	t.Helper()
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(body)
+}
@@ -0,0 +1,36 @@
+package grpcgateway
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+
+	"github.com/yanking/micro-zero/pkg/metrics"
+)
+
+// MetricsMiddleware 返回一个记录请求数和请求耗时指标的网关中间件，指标按匹配的路由模板统计，
+// 例如 "/v1/users/{id}". 通过 WithServeMuxOptions(runtime.WithMiddlewares(MetricsMiddleware())) 使用.
+func MetricsMiddleware() runtime.Middleware {
+	return func(next runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			begin := time.Now()
+			sw := &metrics.StatusWriter{ResponseWriter: w}
+			next(sw, r, pathParams)
+
+			metrics.ObserveHTTPRequest(r.Method, route(r), sw.Status(), time.Since(begin))
+		}
+	}
+}
+
+// route 返回请求匹配的路由模板. 生成的处理器设置了 proto 中定义的路由模板，
+// 通过 HandlePath 注册的处理器没有设置，此时使用网关解析后的路由，例如 "/v1/users/{id=*}".
+func route(r *http.Request) string {
+	if pattern, ok := runtime.HTTPPathPattern(r.Context()); ok {
+		return pattern
+	}
+	if pattern, ok := runtime.HTTPPattern(r.Context()); ok {
+		return pattern.String()
+	}
+	return ""
+}
@@ -0,0 +1,128 @@
+package grpcgateway
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/yanking/micro-zero/pkg/certwatcher"
+	"github.com/yanking/micro-zero/pkg/components/grpcserver"
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+// writeCert writes a self-signed certificate without the loopback address and its key.
+func writeCert(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "apiserver"},
+		DNSNames:     []string{"apiserver.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile = filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	return certFile, keyFile
+}
+
+func TestGatewayVerifiesTLSServer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	certFile, keyFile := writeCert(t, t.TempDir())
+	grpcOpts := options.NewGRPCOptions()
+	grpcOpts.Addr = "127.0.0.1:0"
+	grpcOpts.TLSOptions.Enabled = true
+	grpcOpts.TLSOptions.Cert, grpcOpts.TLSOptions.Key = certFile, keyFile
+	grpcServer, err := grpcserver.New(grpcOpts)
+	require.NoError(t, err)
+	require.NoError(t, grpcServer.Start(ctx))
+	defer grpcServer.Stop(ctx)
+	require.NoError(t, grpcServer.Ready(ctx))
+
+	httpOpts := options.NewHTTPOptions()
+	httpOpts.Addr = "127.0.0.1:0"
+	gatewayGRPCOpts := *grpcOpts
+	gatewayGRPCOpts.Addr = grpcServer.Addr().String()
+
+	register := func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+		client := healthpb.NewHealthClient(conn)
+		return mux.HandlePath(http.MethodGet, "/v1/health", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+			resp, err := client.Check(r.Context(), &healthpb.HealthCheckRequest{})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			_, _ = io.WriteString(w, resp.GetStatus().String())
+		})
+	}
+	gw, err := New(httpOpts, &gatewayGRPCOpts, WithHandlers(register))
+	require.NoError(t, err)
+	require.NoError(t, gw.Start(ctx))
+	defer gw.Stop(ctx)
+
+	// 服务端证书不包含回环地址，网关按证书中的 DNS 名称校验主机名
+	assert.Equal(t, "SERVING", get(t, "http://"+gw.Addr().String()+"/v1/health"))
+
+	// 其他证书不被信任
+	watcher, err := certwatcher.New(certFile, keyFile)
+	require.NoError(t, err)
+	otherCert, otherKey := writeCert(t, t.TempDir())
+	other, err := tls.LoadX509KeyPair(otherCert, otherKey)
+	require.NoError(t, err)
+	leaf, err := x509.ParseCertificate(other.Certificate[0])
+	require.NoError(t, err)
+	assert.Error(t, verifyServer(watcher, "", tls.ConnectionState{PeerCertificates: []*x509.Certificate{leaf}}))
+
+	// 本进程的证书只对证书中的主机名有效
+	own, err := tls.LoadX509KeyPair(certFile, keyFile)
+	require.NoError(t, err)
+	ownLeaf, err := x509.ParseCertificate(own.Certificate[0])
+	require.NoError(t, err)
+	state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{ownLeaf}}
+	assert.NoError(t, verifyServer(watcher, "apiserver.example.com", state))
+	assert.Error(t, verifyServer(watcher, "other.example.com", state))
+}
+
+func TestGatewayRejectsCertificateSignedByClientCA(t *testing.T) {
+	// 由 client-ca 签发的证书不能冒充 gRPC 服务
+	dir := t.TempDir()
+	caFile, caKeyFile := writeCert(t, dir)
+	ca, err := tls.LoadX509KeyPair(caFile, caKeyFile)
+	require.NoError(t, err)
+	caLeaf, err := x509.ParseCertificate(ca.Certificate[0])
+	require.NoError(t, err)
+
+	certFile, keyFile := writeCert(t, t.TempDir())
+	watcher, err := certwatcher.New(certFile, keyFile, certwatcher.WithCAFile(caFile))
+	require.NoError(t, err)
+	assert.Error(t, verifyServer(watcher, "", tls.ConnectionState{PeerCertificates: []*x509.Certificate{caLeaf}}))
+}
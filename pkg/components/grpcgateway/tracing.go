@@ -0,0 +1,79 @@
+package grpcgateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+
+	"github.com/yanking/micro-zero/pkg/metrics"
+	"github.com/yanking/micro-zero/pkg/tracing"
+)
+
+// TracingMiddleware 返回一个为每个请求创建服务端 span 的网关中间件，span 按匹配的路由模板命名，
+// 例如 "GET /v1/users/{id}". 通过 WithServeMuxOptions(runtime.WithMiddlewares(TracingMiddleware())) 使用，
+// 并通过 WithDialOptions(TracingDialOptions()...) 将链路传递给 gRPC 服务.
+func TracingMiddleware() runtime.Middleware {
+	return func(next runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			ctx, span := tracing.StartHTTPServerSpan(r)
+			r = r.WithContext(ctx)
+			sw := &metrics.StatusWriter{ResponseWriter: w}
+			next(sw, r, pathParams)
+
+			tracing.EndHTTPServerSpan(span, r.Method, route(r), sw.Status())
+		}
+	}
+}
+
+// TracingDialOptions 返回连接 gRPC 服务时使用的选项，它们为转发的每个调用创建客户端 span，
+// 并通过 metadata 将链路传递给 gRPC 服务.
+func TracingDialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(tracingUnaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(tracingStreamClientInterceptor),
+	}
+}
+
+func tracingUnaryClientInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := tracing.StartGRPCClientSpan(ctx, method)
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	tracing.EndGRPCSpan(span, err)
+	return err
+}
+
+func tracingStreamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, span := tracing.StartGRPCClientSpan(ctx, method)
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		tracing.EndGRPCSpan(span, err)
+		return nil, err
+	}
+	return &clientStream{ClientStream: cs, span: span}, nil
+}
+
+// clientStream 在流结束时结束客户端 span. 网关在读取到 io.EOF 或出错之前会一直读取响应.
+type clientStream struct {
+	grpc.ClientStream
+	span trace.Span
+	once sync.Once
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			if errors.Is(err, io.EOF) {
+				tracing.EndGRPCSpan(s.span, nil)
+				return
+			}
+			tracing.EndGRPCSpan(s.span, err)
+		})
+	}
+	return err
+}
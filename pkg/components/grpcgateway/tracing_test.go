@@ -0,0 +1,99 @@
+package grpcgateway
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/yanking/micro-zero/pkg/components/grpcserver"
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+func TestTracingPropagatesToGRPCServer(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	grpcOpts := options.NewGRPCOptions()
+	grpcOpts.Addr = "127.0.0.1:0"
+	grpcServer, err := grpcserver.New(grpcOpts, grpcserver.WithUnaryInterceptors(grpcserver.TracingUnaryInterceptor()))
+	require.NoError(t, err)
+	require.NoError(t, grpcServer.Start(ctx))
+	defer grpcServer.Stop(ctx)
+	require.NoError(t, grpcServer.Ready(ctx))
+
+	httpOpts := options.NewHTTPOptions()
+	httpOpts.Addr = "127.0.0.1:0"
+	gatewayGRPCOpts := options.NewGRPCOptions()
+	gatewayGRPCOpts.Addr = grpcServer.Addr().String()
+
+	register := func(ctx context.Context, mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+		client := healthpb.NewHealthClient(conn)
+		return mux.HandlePath(http.MethodGet, "/v1/health/{service}", func(w http.ResponseWriter, r *http.Request, _ map[string]string) {
+			resp, err := client.Check(r.Context(), &healthpb.HealthCheckRequest{})
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadGateway)
+				return
+			}
+			_, _ = io.WriteString(w, resp.GetStatus().String())
+		})
+	}
+	gw, err := New(httpOpts, gatewayGRPCOpts,
+		WithHandlers(register),
+		WithServeMuxOptions(runtime.WithMiddlewares(TracingMiddleware())),
+		WithDialOptions(TracingDialOptions()...),
+	)
+	require.NoError(t, err)
+	require.NoError(t, gw.Start(ctx))
+	defer gw.Stop(ctx)
+	require.NoError(t, gw.Ready(ctx))
+
+	// The trace of the caller is continued by the gateway and the gRPC server.
+	req, err := http.NewRequest(http.MethodGet, "http://"+gw.Addr().String()+"/v1/health/apiserver", nil)
+	require.NoError(t, err)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var gateway, client, server tracesdk.ReadOnlySpan
+	for _, span := range recorder.Ended() {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String(), span.Name())
+		switch span.SpanKind() {
+		case trace.SpanKindServer:
+			if span.Name() == "grpc.health.v1.Health/Check" {
+				server = span
+			} else {
+				gateway = span
+			}
+		case trace.SpanKindClient:
+			client = span
+		}
+	}
+	require.Len(t, recorder.Ended(), 3)
+	require.NotNil(t, gateway)
+	require.NotNil(t, client)
+	require.NotNil(t, server)
+
+	assert.Equal(t, "GET /v1/health/{service=*}", gateway.Name())
+	assert.Equal(t, "00f067aa0ba902b7", gateway.Parent().SpanID().String())
+	assert.Equal(t, gateway.SpanContext().SpanID(), client.Parent().SpanID())
+	assert.Equal(t, client.SpanContext().SpanID(), server.Parent().SpanID())
+}
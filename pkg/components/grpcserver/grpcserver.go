@@ -0,0 +1,263 @@
+package grpcserver
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/yanking/micro-zero/pkg/contract"
+	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+// ComponentName 是 gRPC 服务组件的名称，可用于声明组件依赖.
+const ComponentName = "grpc-server"
+
+var (
+	_ contract.Component          = (*Server)(nil)
+	_ contract.DependentComponent = (*Server)(nil)
+	_ contract.ReadinessChecker   = (*Server)(nil)
+	_ contract.ExitNotifier       = (*Server)(nil)
+)
+
+// RegisterFunc 用于向 gRPC 服务注册业务服务，例如 pb.RegisterUserServiceServer.
+type RegisterFunc func(s grpc.ServiceRegistrar)
+
+// Server 实现了 Component 接口的 gRPC 服务组件.
+// gRPC 服务在 Stop 之后不能再次使用，因此每次 Start 都会创建新的 grpc.Server，支持被容器重启.
+type Server struct {
+	opts         *options.GRPCOptions
+	dependencies []string
+	registers    []RegisterFunc
+	serverOpts   []grpc.ServerOption
+	unary        []grpc.UnaryServerInterceptor
+	stream       []grpc.StreamServerInterceptor
+	reflection   bool
+
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	server   *grpc.Server
+	health   *health.Server
+	listener net.Listener
+	serveErr error
+	// exited 在本次运行的服务异常退出时接收错误
+	exited chan error
+}
+
+// Option 定义了 gRPC 服务组件的可选配置项.
+type Option func(*Server)
+
+// WithDependencies 声明 gRPC 服务组件依赖的组件，容器会在这些组件启动之后再启动 gRPC 服务.
+func WithDependencies(names ...string) Option {
+	return func(s *Server) {
+		s.dependencies = append(s.dependencies, names...)
+	}
+}
+
+// WithServices 注册业务服务，注册函数在每次创建 grpc.Server 时调用.
+func WithServices(registers ...RegisterFunc) Option {
+	return func(s *Server) {
+		s.registers = append(s.registers, registers...)
+	}
+}
+
+// WithUnaryInterceptors 追加一元拦截器，按追加顺序组成拦截器链.
+func WithUnaryInterceptors(interceptors ...grpc.UnaryServerInterceptor) Option {
+	return func(s *Server) {
+		s.unary = append(s.unary, interceptors...)
+	}
+}
+
+// WithStreamInterceptors 追加流式拦截器，按追加顺序组成拦截器链.
+func WithStreamInterceptors(interceptors ...grpc.StreamServerInterceptor) Option {
+	return func(s *Server) {
+		s.stream = append(s.stream, interceptors...)
+	}
+}
+
+// WithServerOptions 追加创建 grpc.Server 时使用的原生选项.
+func WithServerOptions(serverOpts ...grpc.ServerOption) Option {
+	return func(s *Server) {
+		s.serverOpts = append(s.serverOpts, serverOpts...)
+	}
+}
+
+// WithReflection 设置是否注册 gRPC 反射服务，默认开启.
+func WithReflection(enabled bool) Option {
+	return func(s *Server) {
+		s.reflection = enabled
+	}
+}
+
+// New 创建一个新的 gRPC 服务组件实例
+func New(opts *options.GRPCOptions, serverOptions ...Option) (*Server, error) {
+	if opts == nil {
+		return nil, errors.New("grpc options must not be nil")
+	}
+
+	s := &Server{
+		opts:       opts,
+		reflection: true,
+	}
+	for _, o := range serverOptions {
+		o(s)
+	}
+
+	return s, nil
+}
+
+// newServer 创建 grpc.Server 并注册业务服务、健康检查服务和反射服务.
+func (s *Server) newServer(tlsConfig *tls.Config) (*grpc.Server, *health.Server) {
+	serverOpts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(s.unary...),
+		grpc.ChainStreamInterceptor(s.stream...),
+	}
+	if tlsConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	serverOpts = append(serverOpts, s.serverOpts...)
+
+	server := grpc.NewServer(serverOpts...)
+	for _, register := range s.registers {
+		register(server)
+	}
+
+	// 在 Ready 之前对外报告 NOT_SERVING
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	if s.reflection {
+		reflection.Register(server)
+	}
+
+	return server, healthServer
+}
+
+// Start 启动 gRPC 服务组件.
+// 端口在 Start 中同步绑定，绑定失败会直接返回错误；请求的处理在后台 goroutine 中进行.
+func (s *Server) Start(ctx context.Context) error {
+	log.Infof("component: gRPC server starting on %s", s.opts.Addr)
+
+	// 证书的热加载随本次运行结束
+	runCtx, cancel := context.WithCancel(ctx)
+	tlsConfig, err := s.opts.TLSOptions.ServerTLSConfig(runCtx)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	ln, err := net.Listen(s.opts.Network, s.opts.Addr)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to listen on %s: %w", s.opts.Addr, err)
+	}
+
+	server, healthServer := s.newServer(tlsConfig)
+
+	exited := make(chan error, 1)
+	s.mu.Lock()
+	s.cancel = cancel
+	s.server, s.health, s.listener, s.serveErr, s.exited = server, healthServer, ln, nil, exited
+	s.mu.Unlock()
+
+	// 在单独的 goroutine 中启动服务器，以避免阻塞
+	go func() {
+		if err := server.Serve(ln); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Errorf("component: gRPC server error: %v", err)
+			s.mu.Lock()
+			s.serveErr = err
+			s.mu.Unlock()
+			exited <- err
+		}
+	}()
+
+	return nil
+}
+
+// Ready 在端口绑定成功且服务没有异常退出时返回 nil，并将健康检查服务的状态设置为 SERVING
+func (s *Server) Ready(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.serveErr != nil {
+		return s.serveErr
+	}
+	if s.health != nil {
+		s.health.Resume()
+	}
+	return nil
+}
+
+// Exited 返回本次运行的 gRPC 服务异常退出时接收错误的 channel，供容器按监管策略重启组件.
+// 调用 Stop 正常停止时不会发送.
+func (s *Server) Exited() <-chan error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exited
+}
+
+// Stop 优雅停止 gRPC 服务组件.
+// 先将健康检查状态设置为 NOT_SERVING，然后等待进行中的请求完成；
+// 如果 ctx 在此之前结束，则强制关闭所有连接并返回 ctx 的错误.
+func (s *Server) Stop(ctx context.Context) error {
+	log.Infof("component: Stopping gRPC server...")
+
+	s.mu.Lock()
+	server, healthServer, cancel := s.server, s.health, s.cancel
+	s.mu.Unlock()
+	if server == nil {
+		return nil
+	}
+	defer cancel()
+
+	healthServer.Shutdown()
+
+	done := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		log.Warnf("component: gRPC server graceful stop timed out, forcing stop")
+		server.Stop()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// Name 返回组件名称
+func (s *Server) Name() string {
+	return ComponentName
+}
+
+// DependsOn 返回 gRPC 服务组件依赖的组件名称
+func (s *Server) DependsOn() []string {
+	return s.dependencies
+}
+
+// Addr 返回 gRPC 服务实际监听的地址，在 Start 之前返回 nil.
+// 当配置的端口为 0 时，可以通过它获取系统分配的端口.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
@@ -0,0 +1,58 @@
+package grpcserver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+func TestServerLifecycle(t *testing.T) {
+	opts := options.NewGRPCOptions()
+	opts.Addr = "127.0.0.1:0"
+
+	var intercepted []string
+	s, err := New(opts, WithUnaryInterceptors(
+		func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			intercepted = append(intercepted, "first")
+			return handler(ctx, req)
+		},
+		func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+			intercepted = append(intercepted, "second")
+			return handler(ctx, req)
+		},
+	))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	require.NoError(t, s.Start(ctx))
+	conn, err := grpc.NewClient(s.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	resp, err := client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.GetStatus())
+
+	require.NoError(t, s.Ready(ctx))
+	resp, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	assert.Equal(t, []string{"first", "second", "first", "second"}, intercepted)
+
+	require.NoError(t, s.Stop(ctx))
+
+	// 停止之后可以再次启动
+	require.NoError(t, s.Start(ctx))
+	require.NoError(t, s.Stop(ctx))
+}
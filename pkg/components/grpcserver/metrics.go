@@ -0,0 +1,31 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/yanking/micro-zero/pkg/metrics"
+)
+
+// MetricsUnaryInterceptor 返回一个记录一元调用次数和耗时指标的拦截器.
+func MetricsUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		begin := time.Now()
+		resp, err := handler(ctx, req)
+		metrics.ObserveGRPCRequest(info.FullMethod, status.Code(err).String(), time.Since(begin))
+		return resp, err
+	}
+}
+
+// MetricsStreamInterceptor 返回一个记录流式调用次数和耗时指标的拦截器.
+func MetricsStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		begin := time.Now()
+		err := handler(srv, ss)
+		metrics.ObserveGRPCRequest(info.FullMethod, status.Code(err).String(), time.Since(begin))
+		return err
+	}
+}
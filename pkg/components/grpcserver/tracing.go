@@ -0,0 +1,39 @@
+package grpcserver
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+
+	"github.com/yanking/micro-zero/pkg/tracing"
+)
+
+// TracingUnaryInterceptor 返回一个为一元调用创建服务端 span 的拦截器，它沿用 metadata 中传递的链路.
+func TracingUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, span := tracing.StartGRPCServerSpan(ctx, info.FullMethod)
+		resp, err := handler(ctx, req)
+		tracing.EndGRPCSpan(span, err)
+		return resp, err
+	}
+}
+
+// TracingStreamInterceptor 返回一个为流式调用创建服务端 span 的拦截器，它沿用 metadata 中传递的链路.
+func TracingStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, span := tracing.StartGRPCServerSpan(ss.Context(), info.FullMethod)
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		tracing.EndGRPCSpan(span, err)
+		return err
+	}
+}
+
+// serverStream 替换 grpc.ServerStream 的 context，使处理器可以获取 span.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
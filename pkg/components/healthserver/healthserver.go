@@ -0,0 +1,134 @@
+package healthserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/yanking/micro-zero/pkg/contract"
+	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+// ComponentName 是健康检查服务组件的名称，可用于声明组件依赖.
+const ComponentName = "health-server"
+
+var (
+	_ contract.Component        = (*Server)(nil)
+	_ contract.ReadinessChecker = (*Server)(nil)
+	_ contract.ExitNotifier     = (*Server)(nil)
+)
+
+// Server 是提供健康检查、性能分析等管理接口的 HTTP 服务组件.
+// 它不依赖其他组件，会最先启动、最后停止，保证启动和关闭期间探针可用.
+type Server struct {
+	opts       *options.HealthOptions
+	handler    http.Handler
+	installers []func(mux *http.ServeMux)
+
+	mu       sync.Mutex
+	server   *http.Server
+	serveErr error
+	// exited 在本次运行的服务异常退出时接收错误
+	exited chan error
+}
+
+// Option 定义了健康检查服务组件的可选配置项.
+type Option func(*Server)
+
+// WithHandlers 在健康检查服务上注册额外的路由，例如容器的 /livez、/readyz 接口.
+func WithHandlers(install func(mux *http.ServeMux)) Option {
+	return func(s *Server) {
+		s.installers = append(s.installers, install)
+	}
+}
+
+// New 创建一个新的健康检查服务组件实例
+func New(opts *options.HealthOptions, serverOptions ...Option) (contract.Component, error) {
+	if opts == nil {
+		return nil, errors.New("health options must not be nil")
+	}
+
+	s := &Server{opts: opts}
+	for _, o := range serverOptions {
+		o(s)
+	}
+
+	mux := opts.NewServeMux()
+	for _, install := range s.installers {
+		install(mux)
+	}
+	s.handler = mux
+
+	return s, nil
+}
+
+// Start 启动健康检查服务组件，端口在 Start 中同步绑定
+func (s *Server) Start(ctx context.Context) error {
+	log.Infof("component: health server starting on %s", s.opts.HealthCheckAddress)
+
+	ln, err := net.Listen("tcp", s.opts.HealthCheckAddress)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.opts.HealthCheckAddress, err)
+	}
+
+	// http.Server 在 Shutdown 之后不能再次使用，每次启动都创建新的实例
+	server := &http.Server{Handler: s.handler}
+
+	exited := make(chan error, 1)
+	s.mu.Lock()
+	s.server, s.serveErr, s.exited = server, nil, exited
+	s.mu.Unlock()
+
+	go func() {
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("component: health server error: %v", err)
+			s.mu.Lock()
+			s.serveErr = err
+			s.mu.Unlock()
+			exited <- err
+		}
+	}()
+
+	return nil
+}
+
+// Ready 在端口绑定成功且服务没有异常退出时返回 nil
+func (s *Server) Ready(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.serveErr
+}
+
+// Exited 返回本次运行的健康检查服务异常退出时接收错误的 channel，供容器按监管策略重启组件.
+// 调用 Stop 正常停止时不会发送.
+func (s *Server) Exited() <-chan error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exited
+}
+
+// Stop 停止健康检查服务组件
+func (s *Server) Stop(ctx context.Context) error {
+	log.Infof("component: Stopping health server...")
+
+	s.mu.Lock()
+	server := s.server
+	s.mu.Unlock()
+	if server == nil {
+		return nil
+	}
+	return server.Shutdown(ctx)
+}
+
+// Name 返回组件名称
+func (s *Server) Name() string {
+	return ComponentName
+}
@@ -2,81 +2,212 @@ package httpserver
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
-	"time"
+	"sync"
 
 	"github.com/yanking/micro-zero/pkg/contract"
 	"github.com/yanking/micro-zero/pkg/log"
 	"github.com/yanking/micro-zero/pkg/options"
 )
 
-var _ contract.Component = (*Server)(nil)
+// ComponentName 是 HTTP 服务组件的名称，可用于声明组件依赖.
+const ComponentName = "http-server"
 
-// Server 实现了 Component 接口的 HTTP 服务组件
+var (
+	_ contract.Component          = (*Server)(nil)
+	_ contract.DependentComponent = (*Server)(nil)
+	_ contract.ReadinessChecker   = (*Server)(nil)
+	_ contract.ExitNotifier       = (*Server)(nil)
+)
+
+// Middleware 包装一个 http.Handler，用于实现日志、鉴权等通用逻辑.
+type Middleware func(next http.Handler) http.Handler
+
+// RouteFunc 用于在 http.ServeMux 上注册路由，例如 mux.HandleFunc("POST /v1/users", h.CreateUser).
+type RouteFunc func(mux *http.ServeMux)
+
+// Server 实现了 Component 接口的 HTTP 服务组件.
+// 请求处理器可以通过 WithHandler 直接指定，也可以通过 WithRoutes 在内置的 http.ServeMux 上注册路由.
 type Server struct {
-	opts   *options.HTTPOptions
-	server *http.Server
+	opts         *options.HTTPOptions
+	handler      http.Handler
+	routes       []RouteFunc
+	middlewares  []Middleware
+	dependencies []string
+
+	mu       sync.Mutex
+	cancel   context.CancelFunc
+	server   *http.Server
+	listener net.Listener
+	serveErr error
+	// exited 在本次运行的服务异常退出时接收错误
+	exited chan error
+}
+
+// Option 定义了 HTTP 服务组件的可选配置项.
+type Option func(*Server)
+
+// WithDependencies 声明 HTTP 服务组件依赖的组件，容器会在这些组件启动之后再启动 HTTP 服务.
+func WithDependencies(names ...string) Option {
+	return func(s *Server) {
+		s.dependencies = append(s.dependencies, names...)
+	}
+}
+
+// WithHandler 设置处理所有请求的 http.Handler，设置后 WithRoutes 注册的路由不再生效.
+func WithHandler(handler http.Handler) Option {
+	return func(s *Server) {
+		s.handler = handler
+	}
+}
+
+// WithRoutes 在内置的 http.ServeMux 上注册路由.
+func WithRoutes(routes ...RouteFunc) Option {
+	return func(s *Server) {
+		s.routes = append(s.routes, routes...)
+	}
+}
+
+// WithMiddlewares 追加中间件. 中间件按追加顺序由外向内执行，
+// 即第一个中间件最先拿到请求.
+func WithMiddlewares(middlewares ...Middleware) Option {
+	return func(s *Server) {
+		s.middlewares = append(s.middlewares, middlewares...)
+	}
+}
+
+// Chain 将中间件按顺序包装到 handler 上，第一个中间件位于最外层.
+func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
 }
 
 // New 创建一个新的 HTTP 服务组件实例
-func New(opts *options.HTTPOptions) (contract.Component, error) {
-	// 创建一个简单的 HTTP 处理器
-	mux := http.NewServeMux()
-	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		fmt.Fprintf(w, "Hello from HTTP component server!")
-	})
-	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		fmt.Fprintf(w, "OK")
-	})
-
-	// 创建 HTTP 服务器
-	server := &http.Server{
-		Addr:    opts.Addr,
-		Handler: mux,
+func New(opts *options.HTTPOptions, serverOptions ...Option) (*Server, error) {
+	if opts == nil {
+		return nil, errors.New("http options must not be nil")
+	}
+
+	s := &Server{opts: opts}
+	for _, o := range serverOptions {
+		o(s)
+	}
+
+	if s.handler == nil {
+		mux := http.NewServeMux()
+		for _, route := range s.routes {
+			route(mux)
+		}
+		s.handler = recordRoute(mux)
 	}
+	s.handler = Chain(s.handler, s.middlewares...)
 
-	return &Server{
-		opts:   opts,
-		server: server,
-	}, nil
+	return s, nil
 }
 
-// Start 启动 HTTP 服务组件
+// Start 启动 HTTP 服务组件.
+// 端口在 Start 中同步绑定，绑定失败会直接返回错误；请求的处理在后台 goroutine 中进行.
 func (s *Server) Start(ctx context.Context) error {
 	log.Infof("component: HTTP server starting on %s", s.opts.Addr)
 
+	// 证书的热加载随本次运行结束
+	runCtx, cancel := context.WithCancel(ctx)
+	tlsConfig, err := s.opts.TLSOptions.ServerTLSConfig(runCtx)
+	if err != nil {
+		cancel()
+		return err
+	}
+
+	ln, err := net.Listen(s.opts.Network, s.opts.Addr)
+	if err != nil {
+		cancel()
+		return fmt.Errorf("failed to listen on %s: %w", s.opts.Addr, err)
+	}
+
+	// http.Server 在 Shutdown 之后不能再次使用，每次启动都创建新的实例
+	server := &http.Server{Handler: s.handler, TLSConfig: tlsConfig}
+
+	exited := make(chan error, 1)
+	s.mu.Lock()
+	s.cancel = cancel
+	s.server, s.listener, s.serveErr, s.exited = server, ln, nil, exited
+	s.mu.Unlock()
+
 	// 在单独的 goroutine 中启动服务器，以避免阻塞
 	go func() {
-		if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Errorf("component: HTTP server error: %v", err)
+		var err error
+		if tlsConfig != nil {
+			err = server.ServeTLS(ln, "", "")
+		} else {
+			err = server.Serve(ln)
 		}
-	}()
-
-	// 监听上下文取消信号，用于优雅关闭
-	go func() {
-		<-ctx.Done()
-		log.Infof("component: HTTP server shutting down...")
-		// 创建一个带超时的上下文用于关闭
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-
-		if err := s.server.Shutdown(shutdownCtx); err != nil {
-			log.Errorf("component: HTTP server shutdown error: %v", err)
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("component: HTTP server error: %v", err)
+			s.mu.Lock()
+			s.serveErr = err
+			s.mu.Unlock()
+			exited <- err
 		}
 	}()
 
 	return nil
 }
 
+// Ready 在端口绑定成功且服务没有异常退出时返回 nil
+func (s *Server) Ready(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.serveErr
+}
+
+// Exited 返回本次运行的 HTTP 服务异常退出时接收错误的 channel，供容器按监管策略重启组件.
+// 调用 Stop 正常停止时不会发送.
+func (s *Server) Exited() <-chan error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exited
+}
+
 // Stop 停止 HTTP 服务组件
 func (s *Server) Stop(ctx context.Context) error {
 	log.Infof("component: Stopping HTTP server...")
-	return s.server.Shutdown(ctx)
+
+	s.mu.Lock()
+	server, cancel := s.server, s.cancel
+	s.mu.Unlock()
+	if server == nil {
+		return nil
+	}
+	defer cancel()
+	return server.Shutdown(ctx)
 }
 
 // Name 返回组件名称
 func (s *Server) Name() string {
-	return "http-server"
+	return ComponentName
+}
+
+// DependsOn 返回 HTTP 服务组件依赖的组件名称
+func (s *Server) DependsOn() []string {
+	return s.dependencies
+}
+
+// Addr 返回 HTTP 服务实际监听的地址，在 Start 之前返回 nil.
+// 当配置的端口为 0 时，可以通过它获取系统分配的端口.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
 }
@@ -0,0 +1,173 @@
+package httpserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/yanking/micro-zero/pkg/container"
+	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+func TestServerRoutesAndMiddlewares(t *testing.T) {
+	opts := options.NewHTTPOptions()
+	opts.Addr = "127.0.0.1:0"
+
+	var order []string
+	tag := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	s, err := New(opts,
+		WithRoutes(func(mux *http.ServeMux) {
+			mux.HandleFunc("POST /v1/users", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusCreated)
+				_, _ = io.WriteString(w, "created")
+			})
+		}),
+		WithMiddlewares(tag("outer"), tag("inner")),
+	)
+	require.NoError(t, err)
+	assert.Nil(t, s.Addr())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, s.Start(ctx))
+	require.NoError(t, s.Ready(ctx))
+	require.NotNil(t, s.Addr())
+
+	resp, err := http.Post("http://"+s.Addr().String()+"/v1/users", "application/json", nil)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+	assert.Equal(t, "created", string(body))
+	assert.Equal(t, []string{"outer", "inner"}, order)
+
+	require.NoError(t, s.Stop(ctx))
+
+	// 停止之后可以再次启动
+	require.NoError(t, s.Start(ctx))
+	require.NoError(t, s.Stop(ctx))
+}
+
+func TestLogContext(t *testing.T) {
+	var fields []any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		fields = log.FieldsFromContext(r.Context())
+	})
+	handler := Chain(mux, LogContext(func(r *http.Request) string { return r.Header.Get("X-User-ID") }))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/users/7", nil)
+	req.Header.Set(RequestIDHeader, "r-1")
+	req.Header.Set("X-User-ID", "u-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "r-1", rec.Header().Get(RequestIDHeader))
+	require.Len(t, fields, 6)
+	assert.Equal(t, []any{RequestIDKey, "r-1", UserIDKey, "u-1", RouteKey}, fields[:5])
+	assert.Equal(t, "GET /v1/users/{id}", fmt.Sprint(fields[5]))
+
+	// Without a request ID header a new one is generated.
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/users/7", nil))
+	assert.NotEmpty(t, rec.Header().Get(RequestIDHeader))
+}
+
+func TestTracingBeforeLogContext(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder)))
+
+	var fields []any
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		fields = log.FieldsFromContext(r.Context())
+	})
+	handler := Chain(mux, Tracing(), LogContext(nil))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/users/7", nil))
+
+	// LogContext replaces the request, the route matched by the mux is still seen by Tracing.
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /v1/users/{id}", spans[0].Name())
+	require.Len(t, fields, 4)
+	assert.Equal(t, "GET /v1/users/{id}", fmt.Sprint(fields[3]))
+}
+
+// stopper is a critical component that ends the container run when it exits.
+type stopper struct {
+	exited chan error
+}
+
+func (s *stopper) Start(context.Context) error { return nil }
+func (s *stopper) Stop(context.Context) error  { return nil }
+func (s *stopper) Name() string                { return "stopper" }
+func (s *stopper) Exited() <-chan error        { return s.exited }
+
+func TestServerIsRestartedAfterServeFails(t *testing.T) {
+	opts := options.NewHTTPOptions()
+	opts.Addr = "127.0.0.1:0"
+	s, err := New(opts)
+	require.NoError(t, err)
+	stop := &stopper{exited: make(chan error, 1)}
+
+	var starts int
+	var exitErr error
+	c := container.New("test",
+		container.WithShutdownOverallTimeout(time.Second),
+		container.WithSupervision(ComponentName, container.SupervisionPolicy{
+			Restart:        container.RestartOnFailure,
+			MaxRetries:     1,
+			InitialBackoff: time.Millisecond,
+			MaxBackoff:     time.Millisecond,
+			Critical:       true,
+		}),
+		container.WithEventHandler(func(e container.Event) {
+			if e.Component != ComponentName {
+				return
+			}
+			switch e.Type {
+			case container.EventStarted:
+				starts++
+				if starts == 1 {
+					// Kill the running server by closing its listener under it.
+					s.mu.Lock()
+					ln := s.listener
+					s.mu.Unlock()
+					go ln.Close()
+				} else {
+					stop.exited <- errors.New("done")
+				}
+			case container.EventExited:
+				exitErr = e.Err
+			}
+		}),
+	)
+	require.NoError(t, c.Register(s))
+	require.NoError(t, c.Register(stop))
+
+	_, err = c.Run()
+	require.ErrorContains(t, err, "critical component stopper failed: done")
+	assert.Equal(t, 2, starts)
+	assert.Error(t, exitErr)
+}
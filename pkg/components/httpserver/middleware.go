@@ -0,0 +1,140 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/metrics"
+	"github.com/yanking/micro-zero/pkg/tracing"
+)
+
+// RequestIDHeader 是携带请求 ID 的 HTTP 头.
+const RequestIDHeader = "X-Request-ID"
+
+// 日志上下文中的字段名称.
+const (
+	RequestIDKey = "request_id"
+	UserIDKey    = "user_id"
+	RouteKey     = "route"
+)
+
+// UserIDFunc 从请求中解析用户 ID，无法解析时返回空字符串.
+type UserIDFunc func(r *http.Request) string
+
+// LogContext 返回一个中间件，它将请求 ID、用户 ID 和路由通过 log.WithFields 保存到请求的 context 中，
+// 处理器中使用 log.W(r.Context()) 打印的日志都会带有这些字段.
+// 请求 ID 沿用请求头中的值，没有时生成一个新的，并写入响应头. userID 为 nil 时不记录用户 ID.
+func LogContext(userID UserIDFunc) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if id == "" {
+				id = uuid.NewString()
+			}
+			w.Header().Set(RequestIDHeader, id)
+
+			keyvals := []any{RequestIDKey, id}
+			if userID != nil {
+				if uid := userID(r); uid != "" {
+					keyvals = append(keyvals, UserIDKey, uid)
+				}
+			}
+			// http.ServeMux 在匹配路由之后才设置 Pattern，因此在打印日志时才读取路由
+			rt := routeFromContext(r.Context())
+			keyvals = append(keyvals, RouteKey, rt)
+
+			r = withContext(r, log.WithFields(rt.context(r.Context()), keyvals...))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Tracing 返回一个为每个请求创建服务端 span 的中间件，它沿用请求头中传递的链路，
+// span 按 http.ServeMux 匹配的路由命名. 应当放在 LogContext 之前，使请求的日志带有 trace_id 字段.
+func Tracing() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rt := routeFromContext(r.Context())
+			ctx, span := tracing.StartHTTPServerSpan(r)
+			r = withContext(r, rt.context(ctx))
+			sw := &metrics.StatusWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r)
+
+			// 之后的中间件可能替换了请求，匹配的路由通过 context 中共享的 route 读取.
+			// 路由模式的格式为 "[METHOD ][HOST]/[PATH]"，span 名称中的方法由 EndHTTPServerSpan 添加
+			route := rt.pattern()
+			if _, path, ok := strings.Cut(route, " "); ok {
+				route = path
+			}
+			tracing.EndHTTPServerSpan(span, r.Method, route, sw.Status())
+		})
+	}
+}
+
+type routeKey struct{}
+
+// route 记录最终交给 http.ServeMux 的请求，http.ServeMux 会在这个请求上设置匹配的路由模式.
+// 中间件通过 r.WithContext 替换请求之后，外层中间件持有的请求读取不到路由，因此 route 保存在 context 中，
+// 由各个替换请求的中间件共享.
+type route struct {
+	r *http.Request
+}
+
+// routeFromContext 返回 context 中的 route，不存在时返回一个新的 route.
+func routeFromContext(ctx context.Context) *route {
+	if rt, ok := ctx.Value(routeKey{}).(*route); ok {
+		return rt
+	}
+	return &route{}
+}
+
+// context 返回保存了 rt 的 context.
+func (rt *route) context(ctx context.Context) context.Context {
+	if ctx.Value(routeKey{}) == rt {
+		return ctx
+	}
+	return context.WithValue(ctx, routeKey{}, rt)
+}
+
+// pattern 返回请求匹配的路由模式，没有匹配时返回空字符串.
+func (rt *route) pattern() string {
+	if rt.r == nil {
+		return ""
+	}
+	return rt.r.Pattern
+}
+
+// String 返回请求匹配的路由模式，没有匹配的模式时返回请求方法和路径.
+func (rt *route) String() string {
+	if p := rt.pattern(); p != "" {
+		return p
+	}
+	if rt.r == nil {
+		return ""
+	}
+	return rt.r.Method + " " + rt.r.URL.Path
+}
+
+// withContext 返回使用 ctx 的请求副本，并让 ctx 中的 route 指向这个副本.
+func withContext(r *http.Request, ctx context.Context) *http.Request {
+	r = r.WithContext(ctx)
+	if rt, ok := ctx.Value(routeKey{}).(*route); ok {
+		rt.r = r
+	}
+	return r
+}
+
+// recordRoute 让 context 中的 route 指向交给 next 的请求，
+// 使 next 之前的中间件再次替换请求时仍然能读取到 http.ServeMux 匹配的路由.
+func recordRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rt, ok := r.Context().Value(routeKey{}).(*route); ok {
+			rt.r = r
+		}
+		next.ServeHTTP(w, r)
+	})
+}
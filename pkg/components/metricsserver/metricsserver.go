@@ -0,0 +1,152 @@
+package metricsserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+
+	"github.com/yanking/micro-zero/pkg/contract"
+	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/metrics"
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+// ComponentName 是指标服务组件的名称，可用于声明组件依赖.
+const ComponentName = "metrics-server"
+
+var (
+	_ contract.Component        = (*Server)(nil)
+	_ contract.ReadinessChecker = (*Server)(nil)
+	_ contract.ExitNotifier     = (*Server)(nil)
+)
+
+// Server 是以 Prometheus 格式暴露指标的 HTTP 服务组件.
+// 它不依赖其他组件，与健康检查服务一样最先启动、最后停止，保证启动和关闭期间指标可以被采集.
+type Server struct {
+	opts       *options.MetricsOptions
+	handler    http.Handler
+	installers []func(mux *http.ServeMux)
+
+	mu       sync.Mutex
+	server   *http.Server
+	listener net.Listener
+	serveErr error
+	// exited 在本次运行的服务异常退出时接收错误
+	exited chan error
+}
+
+// Option 定义了指标服务组件的可选配置项.
+type Option func(*Server)
+
+// WithHandlers 在指标服务上注册额外的管理接口.
+func WithHandlers(install func(mux *http.ServeMux)) Option {
+	return func(s *Server) {
+		s.installers = append(s.installers, install)
+	}
+}
+
+// New 创建一个新的指标服务组件实例.
+// 它将 opts 中禁用指标、标签白名单和显示隐藏指标的配置应用到全局指标配置后再注册框架的指标，
+// 因此应当在其他组件产生指标之前创建.
+func New(opts *options.MetricsOptions, serverOptions ...Option) (*Server, error) {
+	if opts == nil {
+		return nil, errors.New("metrics options must not be nil")
+	}
+
+	metrics.Apply(opts.Native())
+	metrics.Register()
+
+	s := &Server{opts: opts}
+	for _, o := range serverOptions {
+		o(s)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(opts.Path, metrics.Handler())
+	for _, install := range s.installers {
+		install(mux)
+	}
+	s.handler = mux
+
+	return s, nil
+}
+
+// Start 启动指标服务组件，端口在 Start 中同步绑定
+func (s *Server) Start(ctx context.Context) error {
+	log.Infof("component: metrics server starting on %s", s.opts.Addr)
+
+	ln, err := net.Listen("tcp", s.opts.Addr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.opts.Addr, err)
+	}
+
+	// http.Server 在 Shutdown 之后不能再次使用，每次启动都创建新的实例
+	server := &http.Server{Handler: s.handler}
+
+	exited := make(chan error, 1)
+	s.mu.Lock()
+	s.server, s.listener, s.serveErr, s.exited = server, ln, nil, exited
+	s.mu.Unlock()
+
+	go func() {
+		if err := server.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Errorf("component: metrics server error: %v", err)
+			s.mu.Lock()
+			s.serveErr = err
+			s.mu.Unlock()
+			exited <- err
+		}
+	}()
+
+	return nil
+}
+
+// Ready 在端口绑定成功且服务没有异常退出时返回 nil
+func (s *Server) Ready(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.serveErr
+}
+
+// Exited 返回本次运行的指标服务异常退出时接收错误的 channel，供容器按监管策略重启组件.
+// 调用 Stop 正常停止时不会发送.
+func (s *Server) Exited() <-chan error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.exited
+}
+
+// Stop 停止指标服务组件
+func (s *Server) Stop(ctx context.Context) error {
+	log.Infof("component: Stopping metrics server...")
+
+	s.mu.Lock()
+	server := s.server
+	s.mu.Unlock()
+	if server == nil {
+		return nil
+	}
+	return server.Shutdown(ctx)
+}
+
+// Name 返回组件名称
+func (s *Server) Name() string {
+	return ComponentName
+}
+
+// Addr 返回指标服务实际监听的地址，在 Start 之前返回 nil.
+func (s *Server) Addr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
@@ -0,0 +1,130 @@
+package mongo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"github.com/yanking/micro-zero/pkg/contract"
+	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+// ComponentName 是 MongoDB 组件的名称，可用于声明组件依赖.
+const ComponentName = "mongo-client"
+
+var (
+	_ contract.Component     = (*Client)(nil)
+	_ contract.HealthChecker = (*Client)(nil)
+)
+
+// Client 实现了Component接口的MongoDB组件
+type Client struct {
+	mu     sync.RWMutex
+	opts   *options.MongoOptions
+	client *mongo.Client
+	// cancel 停止本次运行启动的后台 goroutine
+	cancel context.CancelFunc
+}
+
+// New 创建一个新的MongoDB组件实例，连接在 Start 时建立
+func New(opts *options.MongoOptions) *Client {
+	return &Client{
+		opts: opts,
+	}
+}
+
+// Start 连接MongoDB并启动组件. 组件被停止后再次启动时会重新连接，以支持容器的重启策略.
+func (c *Client) Start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	log.Infof("component %s: client starting with database: %s", ComponentName, c.opts.Database)
+
+	// 证书的热加载随本次运行结束
+	ctx, cancel := context.WithCancel(ctx)
+	if c.client == nil {
+		client, err := c.opts.NewClientWithContext(ctx)
+		if err != nil {
+			cancel()
+			return err
+		}
+		c.client = client
+	}
+	c.cancel = cancel
+
+	// 启动一个后台goroutine定期检查连接状态，驱动会自动重连断开的服务器
+	go func() {
+		ticker := time.NewTicker(30 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Infof("component %s: context done, stopping connection checker", ComponentName)
+				return
+			case <-ticker.C:
+				if err := c.HealthCheck(ctx); err != nil && ctx.Err() == nil {
+					log.Errorw(err, "component: MongoDB connection error")
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop 停止MongoDB组件. 断开连接时会等待执行中的操作结束，等待时间受 ctx 限制.
+func (c *Client) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	log.Infof("component %s: Stopping MongoDB client", ComponentName)
+	if c.cancel != nil {
+		c.cancel()
+	}
+	if c.client == nil {
+		return nil
+	}
+
+	err := c.client.Disconnect(ctx)
+	c.client = nil
+	return err
+}
+
+// Name 返回组件名称
+func (c *Client) Name() string {
+	return ComponentName
+}
+
+// HealthCheck 通过 ping 服务器检查连接是否正常，使用配置的读偏好选择服务器
+func (c *Client) HealthCheck(ctx context.Context) error {
+	client := c.GetClient()
+	if client == nil {
+		return errors.New("mongo client is not started")
+	}
+	rp, err := c.opts.ReadPref()
+	if err != nil {
+		return err
+	}
+	return client.Ping(ctx, rp)
+}
+
+// GetClient 获取MongoDB客户端，组件未启动时返回 nil
+func (c *Client) GetClient() *mongo.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client
+}
+
+// GetDatabase 获取配置的数据库，组件未启动时返回 nil
+func (c *Client) GetDatabase() *mongo.Database {
+	client := c.GetClient()
+	if client == nil {
+		return nil
+	}
+	return client.Database(c.opts.Database)
+}
@@ -0,0 +1,29 @@
+package mongo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+func TestClientNotStarted(t *testing.T) {
+	opts := options.NewMongoOptions()
+	opts.URL = "mongodb://127.0.0.1:1"
+	opts.Database = "test"
+	opts.Timeout = 200 * time.Millisecond
+	c := New(opts)
+
+	assert.Nil(t, c.GetClient())
+	assert.Nil(t, c.GetDatabase())
+	assert.Error(t, c.HealthCheck(context.Background()))
+	require.NoError(t, c.Stop(context.Background()), "stopping a component that never started is a no-op")
+
+	// 服务器不可达时 Start 在超时后返回错误
+	assert.Error(t, c.Start(context.Background()))
+	assert.Nil(t, c.GetClient())
+}
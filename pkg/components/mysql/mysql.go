@@ -2,16 +2,22 @@ package mysql
 
 import (
 	"context"
+	"database/sql"
 	"github.com/yanking/micro-zero/pkg/contract"
 	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/metrics"
 	"github.com/yanking/micro-zero/pkg/options"
 	"gorm.io/gorm"
 	"time"
 )
 
-const componentName = "MySQL"
+// ComponentName 是 MySQL 组件的名称，可用于声明组件依赖.
+const ComponentName = "MySQL"
 
-var _ contract.Component = (*Client)(nil)
+var (
+	_ contract.Component     = (*Client)(nil)
+	_ contract.HealthChecker = (*Client)(nil)
+)
 
 type Client struct {
 	opts *options.MySQLOptions
@@ -19,7 +25,7 @@ type Client struct {
 }
 
 func New(opts *options.MySQLOptions) (contract.Component, error) {
-	log.Infof("component %s: client initializing with DSN: %s", componentName, opts.DSN())
+	log.Infof("component %s: client initializing with DSN: %s", ComponentName, opts.DSN())
 	client, err := opts.NewDB()
 	if err != nil {
 		return nil, err
@@ -31,6 +37,15 @@ func New(opts *options.MySQLOptions) (contract.Component, error) {
 }
 
 func (c Client) Start(ctx context.Context) error {
+	// 暴露连接池指标
+	metrics.RegisterDBStats(c.opts.MetricsName(), func() sql.DBStats {
+		sqlDB, err := c.db.DB()
+		if err != nil {
+			return sql.DBStats{}
+		}
+		return sqlDB.Stats()
+	})
+
 	// 启动一个goroutine来处理断线重连
 	go func() {
 		// 定时检查数据库连接状态
@@ -40,16 +55,16 @@ func (c Client) Start(ctx context.Context) error {
 		for {
 			select {
 			case <-ctx.Done():
-				log.Infof("component %s: MySQL component context done, stopping connection checker", componentName)
+				log.Infof("component %s: MySQL component context done, stopping connection checker", ComponentName)
 				return
 			case <-ticker.C:
 				// 检查数据库连接
 				if err := c.ping(); err != nil {
-					log.Errorf("component %s: MySQL connection lost: %v, attempting to reconnect...", componentName, err)
+					log.Errorw(err, "component: MySQL connection lost, attempting to reconnect")
 					if er := c.reconnect(); er != nil {
-						log.Errorf("component %s: failed to reconnect to MySQL: %v", componentName, er)
+						log.Errorw(er, "component: failed to reconnect to MySQL")
 					} else {
-						log.Infof("component %s: successfully reconnected to MySQL", componentName)
+						log.Infof("component %s: successfully reconnected to MySQL", ComponentName)
 					}
 				}
 			}
@@ -59,6 +74,8 @@ func (c Client) Start(ctx context.Context) error {
 }
 
 func (c Client) Stop(ctx context.Context) error {
+	metrics.UnregisterDBStats(c.opts.MetricsName())
+
 	sqlDB, err := c.db.DB()
 	if err != nil {
 		return err
@@ -67,7 +84,7 @@ func (c Client) Stop(ctx context.Context) error {
 }
 
 func (c Client) Name() string {
-	return componentName
+	return ComponentName
 }
 
 // ping 检查数据库连接是否正常
@@ -79,6 +96,15 @@ func (c Client) ping() error {
 	return sqlDB.Ping()
 }
 
+// HealthCheck 通过 ping 数据库检查连接是否正常
+func (c Client) HealthCheck(ctx context.Context) error {
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
 // reconnect 重新连接数据库
 func (c Client) reconnect() error {
 	client, err := c.opts.NewDB()
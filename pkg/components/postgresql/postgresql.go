@@ -0,0 +1,152 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/yanking/micro-zero/pkg/contract"
+	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/metrics"
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+// ComponentName 是 PostgreSQL 组件的名称，可用于声明组件依赖.
+const ComponentName = "PostgreSQL"
+
+var (
+	_ contract.Component     = (*Client)(nil)
+	_ contract.HealthChecker = (*Client)(nil)
+)
+
+// Client 实现了Component接口的PostgreSQL组件
+type Client struct {
+	mu   sync.RWMutex
+	opts *options.PostgreSQLOptions
+	db   *gorm.DB
+	// cancel 停止本次运行启动的后台 goroutine
+	cancel context.CancelFunc
+}
+
+// New 创建一个新的PostgreSQL组件实例，连接失败时返回错误
+func New(opts *options.PostgreSQLOptions) (*Client, error) {
+	log.Infof("component %s: client initializing with addr: %s, database: %s", ComponentName, opts.Addr, opts.Database)
+	db, err := opts.NewDB()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{
+		opts: opts,
+		db:   db,
+	}, nil
+}
+
+// Start 启动PostgreSQL组件. 组件被停止后再次启动时会重新连接数据库，以支持容器的重启策略.
+func (c *Client) Start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.db == nil {
+		db, err := c.opts.NewDB()
+		if err != nil {
+			return err
+		}
+		c.db = db
+	}
+
+	ctx, c.cancel = context.WithCancel(ctx)
+
+	// 暴露连接池指标
+	metrics.RegisterDBStats(c.opts.MetricsName(), func() sql.DBStats {
+		sqlDB, err := c.sqlDB()
+		if err != nil {
+			return sql.DBStats{}
+		}
+		return sqlDB.Stats()
+	})
+
+	// 启动一个后台goroutine定期检查连接状态，断开的连接由连接池在下次使用时重新建立
+	go func() {
+		ticker := time.NewTicker(10 * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				log.Infof("component %s: context done, stopping connection checker", ComponentName)
+				return
+			case <-ticker.C:
+				if err := c.HealthCheck(ctx); err != nil && ctx.Err() == nil {
+					log.Errorw(err, "component: PostgreSQL connection error")
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop 停止PostgreSQL组件. 关闭连接池时会等待执行中的语句结束，等待时间受 ctx 限制.
+func (c *Client) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	log.Infof("component %s: Stopping PostgreSQL client", ComponentName)
+	metrics.UnregisterDBStats(c.opts.MetricsName())
+	if c.cancel != nil {
+		c.cancel()
+	}
+	db := c.db
+	c.db = nil
+	c.mu.Unlock()
+	if db == nil {
+		return nil
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- sqlDB.Close()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Name 返回组件名称
+func (c *Client) Name() string {
+	return ComponentName
+}
+
+// HealthCheck 通过 ping 数据库检查连接是否正常
+func (c *Client) HealthCheck(ctx context.Context) error {
+	sqlDB, err := c.sqlDB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// GetDB 获取数据库连接实例，组件停止后返回 nil
+func (c *Client) GetDB() *gorm.DB {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.db
+}
+
+func (c *Client) sqlDB() (*sql.DB, error) {
+	db := c.GetDB()
+	if db == nil {
+		return nil, errors.New("postgresql client is not started")
+	}
+	return db.DB()
+}
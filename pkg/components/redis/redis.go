@@ -2,40 +2,82 @@ package redis
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/yanking/micro-zero/pkg/config"
 	"github.com/yanking/micro-zero/pkg/contract"
 	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/metrics"
 	"github.com/yanking/micro-zero/pkg/options"
 )
 
-var _ contract.Component = (*Client)(nil)
+// ComponentName 是 Redis 组件的名称，可用于声明组件依赖.
+const ComponentName = "redis-client"
+
+const (
+	// drainTimeout 是 Reconfigure 替换客户端后，等待原客户端的连接归还的最长时间.
+	drainTimeout = 30 * time.Second
+	// drainInterval 是检查原客户端的连接是否全部归还的间隔.
+	drainInterval = 100 * time.Millisecond
+)
+
+var (
+	_ contract.Component     = (*Client)(nil)
+	_ contract.HealthChecker = (*Client)(nil)
+	_ config.Reconfigurable  = (*Client)(nil)
+)
 
 // Client 实现了Component接口的Redis组件
 type Client struct {
+	mu     sync.RWMutex
 	opts   *options.RedisOptions
 	client *redis.Client
+	// cancel 停止本次运行启动的后台 goroutine
+	cancel context.CancelFunc
 }
 
-// New 创建一个新的Redis组件实例
+// New 创建一个新的Redis组件实例，连接在 Start 时建立
 func New(opts *options.RedisOptions) (contract.Component, error) {
-	// 创建Redis客户端
-	client, err := opts.NewClient()
-	if err != nil {
-		return nil, err
+	if opts == nil {
+		return nil, errors.New("redis options must not be nil")
 	}
 
 	return &Client{
-		opts:   opts,
-		client: client,
+		opts: opts,
 	}, nil
 }
 
-// Start 启动Redis组件
+// Start 连接 Redis 并启动组件，Redis 无法连接时返回错误，由容器按监管策略重试.
+// 组件被停止后再次启动时会重新创建客户端，以支持容器的重启策略.
 func (c *Client) Start(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	log.Infof("component: Redis client starting with addr: %s", c.opts.Addr)
 
+	if c.client == nil {
+		client, err := c.opts.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect to redis %s: %w", c.opts.Addr, err)
+		}
+		c.client = client
+	}
+
+	ctx, c.cancel = context.WithCancel(ctx)
+
+	// 暴露连接池指标，客户端可能已被 Reconfigure 替换
+	metrics.RegisterRedisPoolStats(ComponentName, func() *redis.PoolStats {
+		if client := c.GetClient(); client != nil {
+			return client.PoolStats()
+		}
+		return nil
+	})
+
 	// 启动一个后台goroutine定期检查连接状态
 	go func() {
 		ticker := time.NewTicker(30 * time.Second)
@@ -47,9 +89,9 @@ func (c *Client) Start(ctx context.Context) error {
 				log.Infof("component: Redis client context done")
 				return
 			case <-ticker.C:
-				// 检查连接
-				if err := c.client.Ping(ctx).Err(); err != nil {
-					log.Errorf("component: Redis connection error: %v", err)
+				// 检查连接，客户端可能已被 Reconfigure 替换
+				if err := c.HealthCheck(ctx); err != nil {
+					log.Errorw(err, "component: Redis connection error")
 				}
 			}
 		}
@@ -60,16 +102,109 @@ func (c *Client) Start(ctx context.Context) error {
 
 // Stop 停止Redis组件
 func (c *Client) Stop(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	log.Infof("component: Stopping Redis client")
-	return c.client.Close()
+	metrics.UnregisterRedisPoolStats(ComponentName)
+	if c.cancel != nil {
+		c.cancel()
+	}
+	if c.client == nil {
+		return nil
+	}
+
+	client := c.client
+	c.client = nil
+	return client.Close()
 }
 
 // Name 返回组件名称
 func (c *Client) Name() string {
-	return "redis-client"
+	return ComponentName
+}
+
+// HealthCheck 通过 ping Redis 检查连接是否正常
+func (c *Client) HealthCheck(ctx context.Context) error {
+	client := c.GetClient()
+	if client == nil {
+		return errors.New("redis client is not started")
+	}
+	return client.Ping(ctx).Err()
+}
+
+// Reconfigure 在 Redis 配置变化时使用新配置创建客户端，连接成功后替换原来的客户端，
+// 原来的客户端在连接全部归还后关闭. 新客户端无法连接时继续使用原来的客户端.
+func (c *Client) Reconfigure(ctx context.Context, newCfg *config.Config) error {
+	c.mu.RLock()
+	unchanged := reflect.DeepEqual(c.opts, newCfg.RedisOptions)
+	running := c.client != nil
+	c.mu.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	opts := *newCfg.RedisOptions
+	if !running {
+		// 组件未运行时只更新配置，下次启动时生效
+		c.mu.Lock()
+		c.opts = &opts
+		c.mu.Unlock()
+		return nil
+	}
+
+	client, err := opts.NewClient()
+	if err != nil {
+		return err
+	}
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return fmt.Errorf("failed to connect with new redis options: %w", err)
+	}
+
+	c.mu.Lock()
+	old := c.client
+	if old == nil {
+		// 连接期间组件被停止，不再启用新客户端，只更新配置
+		c.opts = &opts
+		c.mu.Unlock()
+		_ = client.Close()
+		return nil
+	}
+	c.opts, c.client = &opts, client
+	c.mu.Unlock()
+
+	log.Infow("component: Redis client reconfigured", "addr", opts.Addr, "pool-size", opts.PoolSize)
+	// 替换前获取了客户端的调用方可能仍在使用它，等待连接归还后再关闭
+	go closeWhenDrained(old, drainTimeout)
+	return nil
+}
+
+// closeWhenDrained 在 client 的连接全部归还连接池或等待 timeout 后关闭 client.
+func closeWhenDrained(client *redis.Client, timeout time.Duration) {
+	ticker := time.NewTicker(drainInterval)
+	defer ticker.Stop()
+	deadline := time.After(timeout)
+
+wait:
+	for {
+		if stats := client.PoolStats(); stats.TotalConns == stats.IdleConns {
+			break
+		}
+		select {
+		case <-ticker.C:
+		case <-deadline:
+			break wait
+		}
+	}
+	if err := client.Close(); err != nil {
+		log.Errorf("component: failed to close replaced Redis client: %v", err)
+	}
 }
 
-// GetClient 返回Redis客户端实例
+// GetClient 返回Redis客户端实例. 配置重新加载后客户端可能被替换，使用方不应长期持有返回值.
 func (c *Client) GetClient() *redis.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 	return c.client
 }
@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+func TestStartFailsWhenRedisIsUnreachable(t *testing.T) {
+	opts := options.NewRedisOptions()
+	opts.Addr = "127.0.0.1:1"
+	opts.MaxRetries = -1
+	opts.DialTimeout = time.Second
+
+	// 创建组件时不连接 Redis，启动失败时由容器按监管策略重试
+	c, err := New(opts)
+	require.NoError(t, err)
+	require.Error(t, c.Start(context.Background()))
+	assert.Nil(t, c.(*Client).GetClient())
+	assert.NoError(t, c.Stop(context.Background()))
+}
+
+func TestCloseWhenDrained(t *testing.T) {
+	// 没有使用中的连接时立即关闭
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	done := make(chan struct{})
+	go func() {
+		closeWhenDrained(client, time.Minute)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("idle client was not closed")
+	}
+	assert.ErrorIs(t, client.Ping(context.Background()).Err(), redis.ErrClosed)
+}
@@ -0,0 +1,254 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+
+	"github.com/yanking/micro-zero/pkg/contract"
+	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/options"
+	"github.com/yanking/micro-zero/pkg/version"
+)
+
+// ComponentName 是链路追踪组件的名称，服务组件应当依赖它，保证停止时先停止服务再导出剩余的 span.
+const ComponentName = "tracing"
+
+var _ contract.Component = (*Provider)(nil)
+
+// Provider 是管理 OpenTelemetry TracerProvider 的组件.
+// Start 根据配置创建导出器、采样器和资源，并将 TracerProvider 和 W3C 传播器设置为全局实例；
+// Stop 导出缓冲中的 span 后关闭 TracerProvider，避免收到 SIGTERM 时丢失 span.
+type Provider struct {
+	opts        *options.TracingOptions
+	serviceName string
+
+	mu       sync.Mutex
+	provider *tracesdk.TracerProvider
+	// closer 关闭 file 导出器打开的文件
+	closer io.Closer
+}
+
+// Option 定义了链路追踪组件的可选配置项.
+type Option func(*Provider)
+
+// WithServiceName 设置配置中没有指定服务名称时使用的服务名称，通常为应用名称.
+func WithServiceName(name string) Option {
+	return func(p *Provider) {
+		p.serviceName = name
+	}
+}
+
+// New 创建一个新的链路追踪组件实例
+func New(opts *options.TracingOptions, providerOptions ...Option) (*Provider, error) {
+	if opts == nil {
+		return nil, errors.New("tracing options must not be nil")
+	}
+
+	p := &Provider{opts: opts}
+	for _, o := range providerOptions {
+		o(p)
+	}
+	if opts.ServiceName != "" {
+		p.serviceName = opts.ServiceName
+	}
+	if p.serviceName == "" {
+		return nil, errors.New("tracing service name must not be empty")
+	}
+
+	return p, nil
+}
+
+// Start 创建 TracerProvider 并设置为全局实例. 组件被停止后再次启动时会重新创建，以支持容器的重启策略.
+func (p *Provider) Start(ctx context.Context) error {
+	log.Infof("component: tracing starting with %s exporter, sampler: %s", p.opts.Exporter, p.opts.Sampler)
+
+	exporter, closer, err := p.newExporter(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create %s trace exporter: %w", p.opts.Exporter, err)
+	}
+
+	res, err := p.newResource(ctx)
+	if err != nil {
+		_ = exporter.Shutdown(ctx)
+		closeQuietly(closer)
+		return fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := tracesdk.NewTracerProvider(
+		tracesdk.WithSampler(p.newSampler()),
+		tracesdk.WithBatcher(exporter),
+		tracesdk.WithResource(res),
+	)
+
+	p.mu.Lock()
+	p.provider, p.closer = provider, closer
+	p.mu.Unlock()
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return nil
+}
+
+// Stop 导出缓冲中的 span 并关闭 TracerProvider，导出受 ctx 的超时时间限制
+func (p *Provider) Stop(ctx context.Context) error {
+	log.Infof("component: Stopping tracing...")
+
+	p.mu.Lock()
+	provider, closer := p.provider, p.closer
+	p.provider, p.closer = nil, nil
+	p.mu.Unlock()
+	if provider == nil {
+		return nil
+	}
+
+	// Shutdown 也会导出剩余的 span，先 ForceFlush 使导出失败时能区分错误原因
+	var errs []error
+	if err := provider.ForceFlush(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to flush spans: %w", err))
+	}
+	if err := provider.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shutdown tracer provider: %w", err))
+	}
+	if closer != nil {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Name 返回组件名称
+func (p *Provider) Name() string {
+	return ComponentName
+}
+
+// TracerProvider 返回当前运行的 TracerProvider，在 Start 之前和 Stop 之后返回 nil.
+func (p *Provider) TracerProvider() *tracesdk.TracerProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.provider
+}
+
+// newExporter 根据配置创建 span 导出器，file 导出器同时返回需要在停止时关闭的文件.
+func (p *Provider) newExporter(ctx context.Context) (tracesdk.SpanExporter, io.Closer, error) {
+	o := p.opts
+	switch o.Exporter {
+	case options.TraceExporterOTLPGRPC:
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithTimeout(o.ExportTimeout)}
+		// 带有 scheme 的地址按 URL 解析，http scheme 表示不使用 TLS
+		if strings.Contains(o.Endpoint, "://") {
+			opts = append(opts, otlptracegrpc.WithEndpointURL(o.Endpoint))
+		} else {
+			opts = append(opts, otlptracegrpc.WithEndpoint(o.Endpoint))
+		}
+		if o.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(o.Headers) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(o.Headers))
+		}
+		exporter, err := otlptracegrpc.New(ctx, opts...)
+		return exporter, nil, err
+	case options.TraceExporterOTLPHTTP:
+		opts := []otlptracehttp.Option{otlptracehttp.WithTimeout(o.ExportTimeout)}
+		if strings.Contains(o.Endpoint, "://") {
+			opts = append(opts, otlptracehttp.WithEndpointURL(o.Endpoint))
+		} else {
+			opts = append(opts, otlptracehttp.WithEndpoint(o.Endpoint))
+		}
+		if o.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		if len(o.Headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(o.Headers))
+		}
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		return exporter, nil, err
+	case options.TraceExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+		return exporter, nil, err
+	case options.TraceExporterFile:
+		// 每个 span 一行 JSON，便于本地运行时使用 jq 等工具查看
+		f, err := os.OpenFile(o.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+		if err != nil {
+			closeQuietly(f)
+			return nil, nil, err
+		}
+		return exporter, f, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported trace exporter %q", o.Exporter)
+	}
+}
+
+// newSampler 根据配置创建采样器，名称与 OTEL_TRACES_SAMPLER 环境变量的取值一致.
+func (p *Provider) newSampler() tracesdk.Sampler {
+	switch p.opts.Sampler {
+	case options.TraceSamplerAlwaysOn:
+		return tracesdk.AlwaysSample()
+	case options.TraceSamplerAlwaysOff:
+		return tracesdk.NeverSample()
+	case options.TraceSamplerTraceIDRatio:
+		return tracesdk.TraceIDRatioBased(p.opts.SampleRatio)
+	case options.TraceSamplerParentBasedAlwaysOn:
+		return tracesdk.ParentBased(tracesdk.AlwaysSample())
+	case options.TraceSamplerParentBasedAlwaysOff:
+		return tracesdk.ParentBased(tracesdk.NeverSample())
+	default:
+		return tracesdk.ParentBased(tracesdk.TraceIDRatioBased(p.opts.SampleRatio))
+	}
+}
+
+// newResource 创建描述当前服务的资源，包含服务名称、版本、部署环境和主机、进程信息.
+// OTEL_RESOURCE_ATTRIBUTES 环境变量中的属性会被配置中的属性覆盖.
+func (p *Provider) newResource(ctx context.Context) (*resource.Resource, error) {
+	info := version.Get()
+	attrs := []attribute.KeyValue{
+		semconv.ServiceName(p.serviceName),
+		semconv.ServiceVersion(info.GitVersion),
+		semconv.VCSRefHeadRevision(info.GitCommit),
+		attribute.String("build.date", info.BuildDate),
+	}
+	if p.opts.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironmentName(p.opts.Environment))
+	}
+	for k, v := range p.opts.Attributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	return resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+		resource.WithProcessPID(),
+		resource.WithProcessRuntimeName(),
+		resource.WithProcessRuntimeVersion(),
+		resource.WithAttributes(attrs...),
+	)
+}
+
+func closeQuietly(c io.Closer) {
+	if c != nil {
+		_ = c.Close()
+	}
+}
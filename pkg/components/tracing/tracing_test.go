@@ -0,0 +1,64 @@
+package tracing
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+
+	"github.com/yanking/micro-zero/pkg/options"
+)
+
+func TestProviderFlushesOnStop(t *testing.T) {
+	opts := options.NewTracingOptions()
+	opts.Enabled = true
+	opts.Exporter = options.TraceExporterFile
+	opts.FilePath = filepath.Join(t.TempDir(), "spans.json")
+	opts.Environment = "test"
+	require.Empty(t, opts.Validate())
+
+	p, err := New(opts, WithServiceName("apiserver"))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Restarting the component creates a new provider.
+	for _, name := range []string{"first run", "second run"} {
+		require.NoError(t, p.Start(ctx))
+		spanCtx, span := otel.Tracer("test").Start(ctx, name)
+		span.End()
+
+		carrier := propagation.MapCarrier{}
+		otel.GetTextMapPropagator().Inject(spanCtx, carrier)
+		assert.NotEmpty(t, carrier.Get("traceparent"))
+
+		// The batch span processor has not exported the span yet, Stop must flush it.
+		require.NoError(t, p.Stop(ctx))
+		assert.Nil(t, p.TracerProvider())
+	}
+
+	data, err := os.ReadFile(opts.FilePath)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"Name":"first run"`)
+	assert.Contains(t, string(data), `"Name":"second run"`)
+	assert.Contains(t, string(data), `"service.name","Value":{"Type":"STRING","Value":"apiserver"}`)
+	assert.Contains(t, string(data), `"deployment.environment.name","Value":{"Type":"STRING","Value":"test"}`)
+}
+
+func TestProviderServiceName(t *testing.T) {
+	_, err := New(options.NewTracingOptions())
+	assert.Error(t, err)
+
+	opts := options.NewTracingOptions()
+	opts.ServiceName = "configured"
+	p, err := New(opts, WithServiceName("apiserver"))
+	require.NoError(t, err)
+	assert.Equal(t, "configured", p.serviceName)
+}
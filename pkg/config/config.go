@@ -29,18 +29,36 @@ type Config struct {
 	JWTKey string `json:"jwt-key" mapstructure:"jwt-key"`
 	// ShutdownOverallTimeout 优雅关闭超时时间.
 	ShutdownOverallTimeout time.Duration `json:"shutdown-overall-timeout" mapstructure:"shutdown-overall-timeout"`
+	// StartupTimeout 等待所有组件启动并就绪的超时时间.
+	StartupTimeout time.Duration `json:"startup-timeout" mapstructure:"startup-timeout"`
+	// ComponentStartTimeout 单个组件启动并就绪的默认超时时间，0 表示只受 StartupTimeout 限制.
+	ComponentStartTimeout time.Duration `json:"component-start-timeout" mapstructure:"component-start-timeout"`
+	// ComponentStopTimeout 单个组件停止的默认超时时间，0 表示只受 ShutdownOverallTimeout 限制.
+	ComponentStopTimeout time.Duration `json:"component-stop-timeout" mapstructure:"component-stop-timeout"`
+	// Supervision 定义组件的监管策略，key 为组件名称. 未配置的组件失败时应用会退出.
+	Supervision map[string]genericoptions.SupervisionOptions `json:"supervision" mapstructure:"supervision"`
 	// LogsOptions 定义日志配置选项.
-	LogsOptions *genericoptions.LogsOptions `json:"logs" mapstructure:"logs"`
+	LogsOptions *genericoptions.LogsOptions `json:"log" mapstructure:"log"`
 	// Expiration 定义 JWT Token 的过期时间.
 	Expiration time.Duration `json:"expiration" mapstructure:"expiration"`
+	// HealthOptions 包含健康检查服务配置选项.
+	HealthOptions *genericoptions.HealthOptions `json:"health" mapstructure:"health"`
+	// MetricsOptions 包含 Prometheus 指标服务配置选项.
+	MetricsOptions *genericoptions.MetricsOptions `json:"metrics" mapstructure:"metrics"`
+	// TracingOptions 包含 OpenTelemetry 链路追踪配置选项.
+	TracingOptions *genericoptions.TracingOptions `json:"tracing" mapstructure:"tracing"`
 	// HTTPOptions 包含 HTTP 配置选项.
 	HTTPOptions *genericoptions.HTTPOptions `json:"http" mapstructure:"http"`
 	// GRPCOptions 包含 gRPC 配置选项.
 	GRPCOptions *genericoptions.GRPCOptions `json:"grpc" mapstructure:"grpc"`
 	// MySQLOptions 包含 MySQL 配置选项.
 	MySQLOptions *genericoptions.MySQLOptions `json:"mysql" mapstructure:"mysql"`
+	// PostgreSQLOptions 包含 PostgreSQL 配置选项，未配置地址时不启用 PostgreSQL.
+	PostgreSQLOptions *genericoptions.PostgreSQLOptions `json:"postgresql" mapstructure:"postgresql"`
 	// RedisOptions 包含 Redis 配置选项.
 	RedisOptions *genericoptions.RedisOptions `json:"redis" mapstructure:"redis"`
+	// MongoOptions 包含 MongoDB 配置选项，未配置 URL 时不启用 MongoDB.
+	MongoOptions *genericoptions.MongoOptions `json:"mongo" mapstructure:"mongo"`
 }
 
 // New 创建带有默认值的 Config 实例.
@@ -50,14 +68,30 @@ func New() *Config {
 		JWTKey:                 "Rtg8BPKNEf2mB4mgvKONGPZZQSaJWNLijxR42qRgq0iBb5",
 		Expiration:             2 * time.Hour,
 		ShutdownOverallTimeout: 20 * time.Second,
+		StartupTimeout:         30 * time.Second,
+		ComponentStopTimeout:   10 * time.Second,
 		LogsOptions:            genericoptions.NewLogsOptions(),
+		HealthOptions:          genericoptions.NewHealthOptions(),
+		MetricsOptions:         genericoptions.NewMetricsOptions(),
+		TracingOptions:         genericoptions.NewTracingOptions(),
 		HTTPOptions:            genericoptions.NewHTTPOptions(),
 		GRPCOptions:            genericoptions.NewGRPCOptions(),
 		MySQLOptions:           genericoptions.NewMySQLOptions(),
+		PostgreSQLOptions:      genericoptions.NewPostgreSQLOptions(),
 		RedisOptions:           genericoptions.NewRedisOptions(),
+		MongoOptions:           genericoptions.NewMongoOptions(),
 	}
 	opts.HTTPOptions.Addr = ":5555"
 	opts.GRPCOptions.Addr = ":6666"
+	// Redis 作为缓存是非关键组件，无法连接时按策略重试，不会导致应用退出
+	opts.Supervision = map[string]genericoptions.SupervisionOptions{
+		"redis-client": {
+			Restart:        genericoptions.RestartOnFailure,
+			MaxRetries:     5,
+			InitialBackoff: time.Second,
+			MaxBackoff:     30 * time.Second,
+		},
+	}
 
 	return opts
 }
@@ -66,12 +100,23 @@ func (c *Config) Flags() (fss cliflag.NamedFlagSets) {
 	fss.FlagSet("global").StringVar(&c.ServerMode, "server-mode", c.ServerMode, fmt.Sprintf("Server mode, available options: %v", availableServerModes.UnsortedList()))
 	fss.FlagSet("global").StringVar(&c.JWTKey, "jwt-key", c.JWTKey, "JWT signing key. Must be at least 6 characters long.")
 	fss.FlagSet("global").DurationVar(&c.Expiration, "expiration", c.Expiration, "The expiration duration of JWT tokens.")
+	fss.FlagSet("global").DurationVar(&c.StartupTimeout, "startup-timeout", c.StartupTimeout, "The maximum duration to wait for all components to start and become ready.")
+	fss.FlagSet("global").DurationVar(&c.ShutdownOverallTimeout, "shutdown-overall-timeout", c.ShutdownOverallTimeout, "The maximum duration of the graceful shutdown of all components.")
+	fss.FlagSet("global").DurationVar(&c.ComponentStartTimeout, "component-start-timeout", c.ComponentStartTimeout, ""+
+		"The default maximum duration for a single component to start and become ready, 0 means only limited by --startup-timeout.")
+	fss.FlagSet("global").DurationVar(&c.ComponentStopTimeout, "component-stop-timeout", c.ComponentStopTimeout, ""+
+		"The default maximum duration for a single component to stop, 0 means only limited by --shutdown-overall-timeout.")
 
 	c.LogsOptions.AddFlags(fss.FlagSet("logs"))
+	c.HealthOptions.AddFlags(fss.FlagSet("health"))
+	c.MetricsOptions.AddFlags(fss.FlagSet("metrics"))
+	c.TracingOptions.AddFlags(fss.FlagSet("tracing"))
 	c.HTTPOptions.AddFlags(fss.FlagSet("HTTP"))
 	c.GRPCOptions.AddFlags(fss.FlagSet("gRPC"))
 	c.MySQLOptions.AddFlags(fss.FlagSet("MySQL"))
+	c.PostgreSQLOptions.AddFlags(fss.FlagSet("PostgreSQL"))
 	c.RedisOptions.AddFlags(fss.FlagSet("Redis"))
+	c.MongoOptions.AddFlags(fss.FlagSet("MongoDB"))
 
 	return fss
 }
@@ -88,12 +133,39 @@ func (c *Config) Validate() error {
 	if len(c.JWTKey) < 6 {
 		errs = append(errs, errors.New("JWTKey must be at least 6 characters long"))
 	}
+	// 校验启动超时时间
+	if c.StartupTimeout <= 0 {
+		errs = append(errs, errors.New("startup-timeout must be greater than 0"))
+	}
+	// 校验关闭超时时间
+	if c.ShutdownOverallTimeout <= 0 {
+		errs = append(errs, errors.New("shutdown-overall-timeout must be greater than 0"))
+	}
+	// 校验单个组件的超时时间，0 表示不单独限制
+	if c.ComponentStartTimeout < 0 {
+		errs = append(errs, errors.New("component-start-timeout must not be negative"))
+	}
+	if c.ComponentStopTimeout < 0 {
+		errs = append(errs, errors.New("component-stop-timeout must not be negative"))
+	}
+
+	// 校验组件监管策略
+	for name, policy := range c.Supervision {
+		for _, err := range policy.Validate() {
+			errs = append(errs, fmt.Errorf("supervision of component %s: %w", name, err))
+		}
+	}
 
 	// 校验子选项
 	errs = append(errs, c.LogsOptions.Validate()...)
+	errs = append(errs, c.HealthOptions.Validate()...)
+	errs = append(errs, c.MetricsOptions.Validate()...)
+	errs = append(errs, c.TracingOptions.Validate()...)
 	errs = append(errs, c.HTTPOptions.Validate()...)
 	errs = append(errs, c.MySQLOptions.Validate()...)
+	errs = append(errs, c.PostgreSQLOptions.Validate()...)
 	errs = append(errs, c.RedisOptions.Validate()...)
+	errs = append(errs, c.MongoOptions.Validate()...)
 
 	// 如果是 gRPC 或 gRPC-Gateway 模式，校验 gRPC 配置
 	if c.ServerMode == known.GRPCServerMode || c.ServerMode == known.GRPCGatewayServerMode {
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	genericoptions "github.com/yanking/micro-zero/pkg/options"
+)
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, New().Validate())
+
+	cfg := New()
+	cfg.ShutdownOverallTimeout = 0
+	cfg.ComponentStartTimeout = -time.Second
+	cfg.ComponentStopTimeout = -time.Second
+	cfg.Supervision = map[string]genericoptions.SupervisionOptions{"worker": {Restart: "sometimes", MaxRetries: -1}}
+	err := cfg.Validate()
+	require.Error(t, err)
+	for _, key := range []string{"shutdown-overall-timeout", "component-start-timeout", "component-stop-timeout", "restart policy", "max-retries"} {
+		assert.Contains(t, err.Error(), key)
+	}
+}
+
+func TestDefaultRedisSupervision(t *testing.T) {
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(strings.NewReader("supervision:\n  worker:\n    restart: always\n")))
+
+	// Configuring other components keeps the non-critical default of the Redis client.
+	cfg := New()
+	require.NoError(t, v.Unmarshal(cfg))
+	require.Contains(t, cfg.Supervision, "redis-client")
+	assert.False(t, cfg.Supervision["redis-client"].Critical)
+	assert.Equal(t, genericoptions.RestartOnFailure, cfg.Supervision["redis-client"].Restart)
+	assert.Equal(t, genericoptions.RestartAlways, cfg.Supervision["worker"].Restart)
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Reconfigurable 由支持在运行时应用新配置的组件实现.
+// 配置文件变化并通过校验后，应用会以新的配置调用 Reconfigure；
+// 组件只需要处理自己关心的配置项，无法在运行时生效的配置项会在重启后生效.
+type Reconfigurable interface {
+	// Reconfigure 应用新的配置，返回错误时组件应继续使用原来的配置.
+	Reconfigure(ctx context.Context, newCfg *Config) error
+}
+
+// liveKeys 是在运行时生效的配置项前缀：日志级别由应用直接修改，Redis 配置由 Redis 组件重新创建客户端.
+var liveKeys = []string{"log.level", "redis."}
+
+// RestartRequired 返回 changed 中重启后才能生效的配置项，例如 HTTP 服务的超时时间：
+// http.Server 在运行时不能安全地修改超时时间.
+func RestartRequired(changed []string) []string {
+	var keys []string
+	for _, key := range changed {
+		live := false
+		for _, prefix := range liveKeys {
+			if key == prefix || (strings.HasSuffix(prefix, ".") && strings.HasPrefix(key, prefix)) {
+				live = true
+				break
+			}
+		}
+		if !live {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// Diff 返回两份配置之间发生变化的配置项，配置项以 "redis.pool-size" 的形式表示并按字母序排列.
+func Diff(oldCfg, newCfg *Config) ([]string, error) {
+	oldValues, err := flatten(oldCfg)
+	if err != nil {
+		return nil, err
+	}
+	newValues, err := flatten(newCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	var changed []string
+	for key, v := range newValues {
+		if old, ok := oldValues[key]; !ok || !reflect.DeepEqual(old, v) {
+			changed = append(changed, key)
+		}
+	}
+	for key := range oldValues {
+		if _, ok := newValues[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// flatten 将配置按 json tag 展开为以点号连接的键值对.
+func flatten(cfg *Config) (map[string]any, error) {
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal config: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	values := make(map[string]any)
+	var walk func(prefix string, v any)
+	walk = func(prefix string, v any) {
+		nested, ok := v.(map[string]any)
+		if !ok || len(nested) == 0 {
+			values[prefix] = v
+			return
+		}
+		for key, value := range nested {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			walk(key, value)
+		}
+	}
+	walk("", m)
+	return values, nil
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiff(t *testing.T) {
+	oldCfg, newCfg := New(), New()
+
+	changed, err := Diff(oldCfg, newCfg)
+	require.NoError(t, err)
+	assert.Empty(t, changed)
+
+	newCfg.RedisOptions.PoolSize = 20
+	newCfg.HTTPOptions.Timeout = time.Minute
+	newCfg.LogsOptions.Level = "debug"
+	changed, err = Diff(oldCfg, newCfg)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"http.timeout", "log.level", "redis.pool-size"}, changed)
+}
+
+func TestRestartRequired(t *testing.T) {
+	changed := []string{"http.timeout", "log.level", "log.level-extra", "redis.pool-size"}
+	assert.Equal(t, []string{"http.timeout", "log.level-extra"}, RestartRequired(changed))
+	assert.Empty(t, RestartRequired([]string{"redis.addr"}))
+}
@@ -6,21 +6,47 @@ import (
 	"fmt"
 	"github.com/yanking/micro-zero/pkg/contract"
 	"github.com/yanking/micro-zero/pkg/log"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"os/signal"
 	"sync"
 	"syscall"
 	"time"
 )
 
+const (
+	// defaultStartupTimeout bounds how long the container waits for all
+	// components to start and become ready.
+	defaultStartupTimeout = 30 * time.Second
+	// defaultShutdownOverallTimeout bounds the whole shutdown sequence.
+	defaultShutdownOverallTimeout = 30 * time.Second
+)
+
 var _ contract.IContainer = (*Container)(nil)
 
 type Container struct {
 	name string
 	// +optional
 	shutdownOverallTimeout time.Duration
-	components             []contract.Component
-	mu                     sync.Mutex
-	wg                     sync.WaitGroup
+	// +optional
+	startupTimeout time.Duration
+	// +optional
+	timeouts componentTimeouts
+	// +optional
+	defaultPolicy SupervisionPolicy
+	// +optional
+	policies map[string]SupervisionPolicy
+	// +optional
+	eventHandlers []EventHandler
+
+	components []contract.Component
+	states     map[string]State
+	running    bool
+	mu         sync.Mutex
+
+	// stopApp cancels the application context with a cause, supervisors
+	// tracks the goroutines watching and restarting components.
+	stopApp     context.CancelCauseFunc
+	supervisors sync.WaitGroup
 }
 
 func (c *Container) Name() string {
@@ -37,10 +63,24 @@ func WithShutdownOverallTimeout(t time.Duration) Option {
 	}
 }
 
+// WithStartupTimeout sets how long the container waits for all components to
+// start and become ready before it gives up and shuts the application down.
+func WithStartupTimeout(t time.Duration) Option {
+	return func(c *Container) {
+		c.startupTimeout = t
+	}
+}
+
 func New(name string, opts ...Option) *Container {
 	c := &Container{
-		name:       name,
-		components: make([]contract.Component, 0),
+		name:                   name,
+		shutdownOverallTimeout: defaultShutdownOverallTimeout,
+		startupTimeout:         defaultStartupTimeout,
+		timeouts:               newComponentTimeouts(),
+		defaultPolicy:          DefaultSupervisionPolicy(),
+		policies:               make(map[string]SupervisionPolicy),
+		components:             make([]contract.Component, 0),
+		states:                 make(map[string]State),
 	}
 
 	for _, o := range opts {
@@ -50,34 +90,76 @@ func New(name string, opts ...Option) *Container {
 	return c
 }
 
-func (c *Container) Register(cp contract.Component) {
-	c.components = append(c.components, cp)
-	log.Infof("container: register component: %s", c.Name())
+// Register adds a component to the container. Components implementing
+// contract.DependentComponent may depend on components that are registered
+// later, but a registration that closes a dependency cycle is rejected.
+func (c *Container) Register(cp contract.Component) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, registered := range c.components {
+		if registered.Name() == cp.Name() {
+			return fmt.Errorf("container: component %s is already registered", cp.Name())
+		}
+	}
+
+	components := append(c.components[:len(c.components):len(c.components)], cp)
+	if _, err := topologicalWaves(components, false); err != nil {
+		return fmt.Errorf("container: failed to register component %s: %w", cp.Name(), err)
+	}
+
+	c.components = components
+	c.states[cp.Name()] = StatePending
+	log.Infof("container: register component: %s", cp.Name())
+	return nil
 }
 
-func (c *Container) Run() error {
+// Run starts all registered components, blocks until the application is
+// asked to stop or a component fails to start, and then stops the started
+// components gracefully. The returned report describes how stopping each
+// component went; it is nil if the application never started.
+func (c *Container) Run() (*contract.ShutdownReport, error) {
+	c.mu.Lock()
+	waves, err := topologicalWaves(c.components, true)
+	c.mu.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("container: invalid component dependencies: %w", err)
+	}
+
 	// Create a context that gets cancelled on OS signal (SIGINT, SIGTERM)
-	// or if any critical component's Start method calls the 'appStopCauseError' function.
-	appCtx, appStopCauseError := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer appStopCauseError() // Important to release resources
-
-	// Start all registered container components concurrently
-	for _, comp := range c.components {
-		cp := comp // Capture range variable for goroutine
-		c.wg.Add(1)
-		go func(cp contract.Component) {
-			defer c.wg.Done()
-			log.Infof("container: starting component: %s", cp.Name())
-			if err := cp.Start(appCtx); err != nil { // Pass the container's cancellable context
-				log.Errorf("container: error starting component %s: %v. initiating application shutdown.", cp.Name(), err)
-				appStopCauseError() // Trigger shutdown for all other components
-			}
-			// If Start is blocking and succeeds, it will run until appCtx is cancelled.
-			// If Start is non-blocking, this goroutine will exit after Start returns.
-			// We need to ensure Start methods handle appCtx cancellation properly if they are long-running.
-		}(cp)
+	// or if any critical component's Start method fails.
+	signalCtx, stopSignal := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignal() // Important to release resources
+	appCtx, appStopCauseError := context.WithCancelCause(signalCtx)
+	defer appStopCauseError(nil)
+	c.stopApp = appStopCauseError
+
+	// Start components wave by wave in dependency order. Components of the
+	// same wave do not depend on each other and are started concurrently.
+	// Start methods are expected to return once the component is started and
+	// keep any long-running work in goroutines bound to the passed context.
+	// The whole startup, including waiting for readiness, is bounded by the
+	// startup timeout.
+	startupCtx, cancelStartup := context.WithTimeout(appCtx, c.startupTimeout)
+	var started [][]contract.Component
+	for _, wave := range waves {
+		if appCtx.Err() != nil {
+			break
+		}
+
+		started = append(started, wave)
+		if err := c.startWave(appCtx, startupCtx, wave); err != nil {
+			log.Errorf("container: %v. initiating application shutdown.", err)
+			appStopCauseError(fmt.Errorf("startup failed: %w", err)) // Trigger shutdown for all other components
+		}
+	}
+	cancelStartup()
+	if appCtx.Err() == nil {
+		c.mu.Lock()
+		c.running = true
+		c.mu.Unlock()
+		log.Infof("container: all components started and ready. application %s is running.", c.name)
 	}
-	log.Infof("container: all components initiated for start. application %s is running.", c.name)
 
 	// Block here until the appCtx is cancelled
 	<-appCtx.Done()
@@ -89,43 +171,177 @@ func (c *Container) Run() error {
 	}
 
 	// --- Graceful Shutdown Procedure ---
+	c.mu.Lock()
+	c.running = false
+	c.mu.Unlock()
 	log.Infof("container: initiating graceful stop of application %s...", c.name)
 
+	// Wait for the supervisors to notice the cancellation, so that no
+	// component gets restarted while it is being stopped.
+	c.supervisors.Wait()
+
+	report := c.stopAll(started, errCause)
+	if err := report.Err(); err != nil {
+		log.Errorf("container: application %s stopped with errors in %v: %v", c.name, report.Duration, err)
+	} else {
+		log.Infof("container: application %s stopped gracefully in %v.", c.name, report.Duration)
+	}
+
+	// Check if shutdown was due to an error from context.Cause or normal signal.
+	// signal.NotifyContext cancels with context.Canceled on signal.
+	// If it was another error, that might be the one to return from Run().
+	if errCause != nil && !errors.Is(errCause, context.Canceled) {
+		return report, fmt.Errorf("application shutdown due to error: %w", errCause)
+	}
+	return report, nil // Graceful shutdown completed
+}
+
+// stopAll stops the started components in reverse topological order, so that
+// a component is always stopped before the components it depends on.
+// Components of the same tier are stopped in parallel.
+func (c *Container) stopAll(started [][]contract.Component, cause error) *contract.ShutdownReport {
+	report := &contract.ShutdownReport{}
+	if cause != nil && !errors.Is(cause, context.Canceled) {
+		report.Cause = cause
+	}
+
 	// Create a new context for the shutdown procedure itself, with a timeout.
-	// This timeout is for the *entire* shutdown sequence of all components.
+	// This timeout is for the *entire* shutdown sequence of all components,
+	// each component is additionally bounded by its own stop timeout.
 	stopCtx, cancelStopCtx := context.WithTimeout(context.Background(), c.shutdownOverallTimeout)
 	defer cancelStopCtx()
 
-	// Stop components in reverse order of registration (LIFO).
-	// This assumes a simple dependency order; more complex apps might need a dependency graph.
-	for i := len(c.components) - 1; i >= 0; i-- {
-		comp := c.components[i]
-		log.Infof("container: attempting to stop component: %s", comp.Name())
-
-		// We pass stopCtx to each component's Stop method.
-		// The component's Stop method should respect this context's deadline.
-		if err := comp.Stop(stopCtx); err != nil {
-			log.Errorf("container: error stopping component %s: %v", comp.Name(), err)
-		} else {
-			log.Infof("container: component %s stopped successfully.", comp.Name())
+	begin := time.Now()
+	for i := len(started) - 1; i >= 0; i-- {
+		report.Components = append(report.Components, c.stopWave(stopCtx, started[i])...)
+	}
+	report.Duration = time.Since(begin)
+
+	return report
+}
+
+// stopWave stops the components of one tier concurrently and returns their
+// stop reports in registration order.
+func (c *Container) stopWave(stopCtx context.Context, wave []contract.Component) []contract.ComponentStopReport {
+	reports := make([]contract.ComponentStopReport, len(wave))
+
+	var wg sync.WaitGroup
+	for i, cp := range wave {
+		wg.Add(1)
+		go func(i int, cp contract.Component) {
+			defer wg.Done()
+			reports[i] = c.stopComponent(stopCtx, cp)
+		}(i, cp)
+	}
+	wg.Wait()
+
+	return reports
+}
+
+// stopComponent stops a single component bounded by its stop timeout.
+func (c *Container) stopComponent(stopCtx context.Context, cp contract.Component) contract.ComponentStopReport {
+	log.Infof("container: attempting to stop component: %s", cp.Name())
+	c.setState(cp.Name(), StateStopping)
+
+	ctx := stopCtx
+	if timeout := c.timeouts.stopTimeout(cp); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(stopCtx, timeout)
+		defer cancel()
+	}
+
+	// The component's Stop method should respect this context's deadline.
+	begin := time.Now()
+	err := callWithContext(ctx, func() error { return cp.Stop(ctx) })
+	cr := contract.ComponentStopReport{Name: cp.Name(), Duration: time.Since(begin), Err: err}
+	if err != nil {
+		log.Errorf("container: error stopping component %s after %v: %v", cp.Name(), cr.Duration, err)
+	} else {
+		log.Infof("container: component %s stopped successfully in %v.", cp.Name(), cr.Duration)
+	}
+	c.setState(cp.Name(), StateStopped)
+	c.emit(Event{Type: EventStopped, Component: cp.Name(), Duration: cr.Duration, Err: err})
+
+	return cr
+}
+
+// startWave starts the components of one wave concurrently and waits until
+// all of them are ready. Errors of all failing critical components are
+// aggregated.
+func (c *Container) startWave(appCtx, startupCtx context.Context, wave []contract.Component) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+
+	for _, cp := range wave {
+		wg.Add(1)
+		go func(cp contract.Component) {
+			defer wg.Done()
+			if err := c.startSupervised(appCtx, startupCtx, cp); err != nil {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(cp)
+	}
+	wg.Wait()
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// startComponent starts a single component and, if it implements
+// contract.ReadinessChecker, waits until it reports ready. Both steps are
+// bounded by the component's start timeout and the overall startup timeout.
+func (c *Container) startComponent(appCtx, startupCtx context.Context, cp contract.Component) error {
+	log.Infof("container: starting component: %s", cp.Name())
+	c.setState(cp.Name(), StateStarting)
+
+	ctx, timeout := startupCtx, c.startupTimeout
+	if t := c.timeouts.startTimeout(cp); t > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(startupCtx, t)
+		defer cancel()
+		timeout = min(t, timeout)
+	}
+
+	// Pass the container's cancellable context to Start, the component may
+	// bind long-running work to it. The start timeout is enforced by not
+	// waiting for Start any longer than allowed.
+	if err := callWithContext(ctx, func() error { return cp.Start(appCtx) }); err != nil {
+		if ctx.Err() != nil && appCtx.Err() == nil {
+			return fmt.Errorf("component %s did not start within %v: %w", cp.Name(), timeout, err)
 		}
+		return fmt.Errorf("component %s failed to start: %w", cp.Name(), err)
 	}
 
-	// Wait for all goroutines launched by `go func() { c.Start(appCtx) }` to complete.
-	// This is crucial because if a component's Start() errors out and calls appStopCauseError(),
-	// its goroutine might still be running. We need to ensure all these initial goroutines
-	// have finished before declaring the container fully stopped.
-	log.Infof("container: waiting for initial component start goroutines to complete...")
-	c.wg.Wait()
-	log.Infof("container: all initial component start goroutines have completed.")
+	if rc, ok := cp.(contract.ReadinessChecker); ok {
+		if err := rc.Ready(ctx); err != nil {
+			if ctx.Err() != nil && appCtx.Err() == nil {
+				return fmt.Errorf("component %s did not become ready within %v: %w", cp.Name(), timeout, err)
+			}
+			return fmt.Errorf("component %s failed to become ready: %w", cp.Name(), err)
+		}
+	}
 
-	log.Infof("container: application %s stopped gracefully.", c.name)
+	log.Infof("container: component %s is ready", cp.Name())
+	return nil
+}
 
-	// Check if shutdown was due to an error from context.Cause or normal signal.
-	// signal.NotifyContext cancels with context.Canceled on signal.
-	// If it was another error, that might be the one to return from Run().
-	if errCause != nil && !errors.Is(errCause, context.Canceled) {
-		return fmt.Errorf("application shutdown due to error: %w", errCause)
+// callWithContext runs fn and waits for it to return or for ctx to be done,
+// whichever happens first. If ctx is done first, fn keeps running in the
+// background and the context error is returned.
+func callWithContext(ctx context.Context, fn func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- fn()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
 	}
-	return nil // Graceful shutdown completed
 }
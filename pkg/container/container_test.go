@@ -0,0 +1,374 @@
+package container
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder collects the order in which components are started and stopped.
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (r *recorder) add(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) list() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.events...)
+}
+
+type fakeComponent struct {
+	name      string
+	deps      []string
+	startErr  error
+	stopErr   error
+	stopDelay time.Duration
+	rec       *recorder
+}
+
+func (f *fakeComponent) Start(ctx context.Context) error {
+	f.rec.add("start:" + f.name)
+	return f.startErr
+}
+
+func (f *fakeComponent) Stop(ctx context.Context) error {
+	f.rec.add("stop:" + f.name)
+	if f.stopDelay > 0 {
+		select {
+		case <-time.After(f.stopDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return f.stopErr
+}
+
+func (f *fakeComponent) Name() string        { return f.name }
+func (f *fakeComponent) DependsOn() []string { return f.deps }
+
+// readyComponent becomes ready when its ready channel is closed.
+type readyComponent struct {
+	fakeComponent
+	ready chan struct{}
+}
+
+func (r *readyComponent) Ready(ctx context.Context) error {
+	select {
+	case <-r.ready:
+		r.rec.add("ready:" + r.name)
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func waveNames(t *testing.T, c *Container) [][]string {
+	t.Helper()
+	waves, err := topologicalWaves(c.components, true)
+	require.NoError(t, err)
+
+	var result [][]string
+	for _, wave := range waves {
+		var ws []string
+		for _, cp := range wave {
+			ws = append(ws, cp.Name())
+		}
+		result = append(result, ws)
+	}
+	return result
+}
+
+func TestTopologicalWaves(t *testing.T) {
+	rec := &recorder{}
+	c := New("test")
+	require.NoError(t, c.Register(&fakeComponent{name: "http", deps: []string{"mysql", "redis"}, rec: rec}))
+	require.NoError(t, c.Register(&fakeComponent{name: "redis", rec: rec}))
+	require.NoError(t, c.Register(&fakeComponent{name: "cache", deps: []string{"redis"}, rec: rec}))
+	require.NoError(t, c.Register(&fakeComponent{name: "mysql", rec: rec}))
+
+	assert.Equal(t, [][]string{{"redis", "mysql"}, {"http", "cache"}}, waveNames(t, c))
+}
+
+func TestRegisterRejectsCycles(t *testing.T) {
+	rec := &recorder{}
+	c := New("test")
+	require.NoError(t, c.Register(&fakeComponent{name: "a", deps: []string{"b"}, rec: rec}))
+	require.NoError(t, c.Register(&fakeComponent{name: "b", deps: []string{"c"}, rec: rec}))
+
+	err := c.Register(&fakeComponent{name: "c", deps: []string{"a"}, rec: rec})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dependency cycle detected")
+	assert.Len(t, c.components, 2)
+
+	assert.Error(t, c.Register(&fakeComponent{name: "self", deps: []string{"self"}, rec: rec}))
+	assert.Error(t, c.Register(&fakeComponent{name: "a", rec: rec}))
+}
+
+func TestRunRejectsMissingDependencies(t *testing.T) {
+	c := New("test", WithShutdownOverallTimeout(time.Second))
+	require.NoError(t, c.Register(&fakeComponent{name: "http", deps: []string{"mysql"}, rec: &recorder{}}))
+
+	_, err := c.Run()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unregistered component mysql")
+}
+
+func TestRunStartsAndStopsInDependencyOrder(t *testing.T) {
+	rec := &recorder{}
+	startErr := errors.New("boom")
+
+	c := New("test", WithShutdownOverallTimeout(time.Second))
+	require.NoError(t, c.Register(&fakeComponent{name: "http", deps: []string{"mysql"}, rec: rec}))
+	require.NoError(t, c.Register(&fakeComponent{name: "mysql", rec: rec}))
+	require.NoError(t, c.Register(&fakeComponent{name: "worker", deps: []string{"http"}, startErr: startErr, rec: rec}))
+
+	_, err := c.Run()
+	require.Error(t, err)
+	assert.ErrorIs(t, err, startErr)
+	assert.Equal(t, []string{
+		"start:mysql", "start:http", "start:worker",
+		"stop:worker", "stop:http", "stop:mysql",
+	}, rec.list())
+}
+
+func TestRunWaitsForReadiness(t *testing.T) {
+	rec := &recorder{}
+	db := &readyComponent{fakeComponent: fakeComponent{name: "db", rec: rec}, ready: make(chan struct{})}
+	worker := &fakeComponent{name: "worker", deps: []string{"db", "http"}, startErr: errors.New("stop"), rec: rec}
+
+	c := New("test", WithShutdownOverallTimeout(time.Second))
+	require.NoError(t, c.Register(db))
+	require.NoError(t, c.Register(&fakeComponent{name: "http", deps: []string{"db"}, rec: rec}))
+	require.NoError(t, c.Register(worker))
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		state, _ := c.State("db")
+		assert.Equal(t, StateStarting, state)
+		assert.False(t, c.Ready())
+		close(db.ready)
+	}()
+
+	_, err := c.Run()
+	require.Error(t, err)
+	assert.Equal(t, []string{"start:db", "ready:db", "start:http", "start:worker"}, rec.list()[:4])
+	assert.Equal(t, map[string]State{"db": StateStopped, "http": StateStopped, "worker": StateStopped}, c.States())
+}
+
+func TestRunFailsWhenComponentIsNotReadyInTime(t *testing.T) {
+	rec := &recorder{}
+	slow := &readyComponent{fakeComponent: fakeComponent{name: "slow", rec: rec}, ready: make(chan struct{})}
+
+	c := New("test", WithShutdownOverallTimeout(time.Second), WithStartupTimeout(50*time.Millisecond))
+	require.NoError(t, c.Register(slow))
+	require.NoError(t, c.Register(&fakeComponent{name: "http", deps: []string{"slow"}, rec: rec}))
+
+	_, err := c.Run()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "component slow did not become ready within 50ms")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []string{"start:slow", "stop:slow"}, rec.list())
+}
+
+// slowStopper reports its own stop timeout.
+type slowStopper struct {
+	fakeComponent
+	timeout time.Duration
+}
+
+func (s *slowStopper) StopTimeout() time.Duration { return s.timeout }
+
+func TestRunReturnsShutdownReport(t *testing.T) {
+	rec := &recorder{}
+	stopErr := errors.New("close failed")
+
+	c := New("test",
+		WithShutdownOverallTimeout(time.Second),
+		WithStopTimeout(500*time.Millisecond),
+		WithComponentStopTimeout("mysql", 20*time.Millisecond),
+	)
+	require.NoError(t, c.Register(&fakeComponent{name: "mysql", stopDelay: time.Second, rec: rec}))
+	require.NoError(t, c.Register(&slowStopper{fakeComponent: fakeComponent{name: "redis", stopDelay: time.Second, rec: rec}, timeout: 20 * time.Millisecond}))
+	require.NoError(t, c.Register(&fakeComponent{name: "kafka", stopErr: stopErr, rec: rec}))
+	require.NoError(t, c.Register(&fakeComponent{name: "http", deps: []string{"mysql", "redis", "kafka"}, startErr: errors.New("bind"), rec: rec}))
+
+	report, err := c.Run()
+	require.Error(t, err)
+	require.NotNil(t, report)
+	assert.ErrorContains(t, report.Cause, "bind")
+
+	var stopped []string
+	for _, cr := range report.Components {
+		stopped = append(stopped, cr.Name)
+	}
+	assert.Equal(t, []string{"http", "mysql", "redis", "kafka"}, stopped)
+
+	// mysql and redis hit their own stop timeouts and are stopped in parallel,
+	// so neither can use up the budget of the other.
+	assert.ErrorIs(t, report.Components[1].Err, context.DeadlineExceeded)
+	assert.ErrorIs(t, report.Components[2].Err, context.DeadlineExceeded)
+	assert.Less(t, report.Duration, 400*time.Millisecond)
+
+	agg := report.Err()
+	require.Error(t, agg)
+	assert.ErrorIs(t, agg, stopErr)
+	assert.Contains(t, agg.Error(), "component kafka: close failed")
+}
+
+func TestRunFailsWhenComponentDoesNotStartInTime(t *testing.T) {
+	blocking := &readyComponent{fakeComponent: fakeComponent{name: "blocking", rec: &recorder{}}, ready: make(chan struct{})}
+
+	c := New("test", WithShutdownOverallTimeout(time.Second), WithComponentStartTimeout("blocking", 20*time.Millisecond))
+	require.NoError(t, c.Register(blocking))
+
+	_, err := c.Run()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "component blocking did not become ready within 20ms")
+}
+
+func TestComponentTimeoutsMatchNamesCaseInsensitively(t *testing.T) {
+	// Like supervision policies, timeouts may come from lower-cased config keys.
+	c := New("test", WithComponentStartTimeout("gRPC-Server", time.Second), WithComponentStopTimeout("grpc-server", 2*time.Second))
+	cp := &fakeComponent{name: "GRPC-server", rec: &recorder{}}
+	assert.Equal(t, time.Second, c.timeouts.startTimeout(cp))
+	assert.Equal(t, 2*time.Second, c.timeouts.stopTimeout(cp))
+}
+
+// flakyComponent fails to start with the queued errors and exposes its
+// background work through Exited.
+type flakyComponent struct {
+	fakeComponent
+	mu        sync.Mutex
+	startErrs []error
+	exited    chan error
+}
+
+func (f *flakyComponent) Start(ctx context.Context) error {
+	f.rec.add("start:" + f.name)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.exited = make(chan error, 1)
+	if len(f.startErrs) > 0 {
+		err := f.startErrs[0]
+		f.startErrs = f.startErrs[1:]
+		return err
+	}
+	return nil
+}
+
+func (f *flakyComponent) Exited() <-chan error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.exited
+}
+
+func (f *flakyComponent) exit(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.exited <- err
+}
+
+func fastRestart(restart RestartPolicy, maxRetries int, critical bool) SupervisionPolicy {
+	return SupervisionPolicy{
+		Restart:        restart,
+		MaxRetries:     maxRetries,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Critical:       critical,
+	}
+}
+
+func TestSupervisionPolicyBackoff(t *testing.T) {
+	p := SupervisionPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	assert.Equal(t, 100*time.Millisecond, p.backoff(1))
+	assert.Equal(t, 400*time.Millisecond, p.backoff(3))
+	assert.Equal(t, time.Second, p.backoff(10))
+
+	assert.False(t, fastRestart(RestartNever, 0, true).allowsRestart(errors.New("x"), 1))
+	assert.True(t, fastRestart(RestartOnFailure, 2, true).allowsRestart(errors.New("x"), 2))
+	assert.False(t, fastRestart(RestartOnFailure, 2, true).allowsRestart(errors.New("x"), 3))
+	assert.False(t, fastRestart(RestartOnFailure, 0, true).allowsRestart(nil, 1))
+	assert.True(t, fastRestart(RestartAlways, 0, true).allowsRestart(nil, 1))
+}
+
+func TestNonCriticalComponentIsRestarted(t *testing.T) {
+	rec := &recorder{}
+	cache := &flakyComponent{fakeComponent: fakeComponent{name: "cache", rec: rec}, startErrs: []error{errors.New("refused"), errors.New("refused")}}
+	trigger := &flakyComponent{fakeComponent: fakeComponent{name: "trigger", rec: rec}}
+
+	var mu sync.Mutex
+	var events []Event
+	c := New("test",
+		WithShutdownOverallTimeout(time.Second),
+		WithSupervision("cache", fastRestart(RestartOnFailure, 3, false)),
+		WithEventHandler(func(e Event) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+			if e.Component == "cache" && e.Type == EventStarted {
+				// Once the cache is back, let the critical component fail
+				// to end the test run.
+				go trigger.exit(errors.New("fatal"))
+			}
+		}),
+	)
+	require.NoError(t, c.Register(cache))
+	require.NoError(t, c.Register(trigger))
+
+	_, err := c.Run()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "critical component trigger failed: fatal")
+
+	mu.Lock()
+	defer mu.Unlock()
+	var restarts int
+	for _, e := range events {
+		if e.Component == "cache" && e.Type == EventRestarting {
+			restarts++
+		}
+	}
+	assert.Equal(t, 2, restarts)
+}
+
+func TestCriticalComponentGivesUpAfterMaxRetries(t *testing.T) {
+	rec := &recorder{}
+	worker := &flakyComponent{fakeComponent: fakeComponent{name: "worker", rec: rec}}
+
+	var gaveUp bool
+	c := New("test",
+		WithShutdownOverallTimeout(time.Second),
+		WithSupervision("worker", fastRestart(RestartOnFailure, 1, true)),
+		WithEventHandler(func(e Event) {
+			switch e.Type {
+			case EventStarted:
+				if !gaveUp {
+					worker.mu.Lock()
+					worker.startErrs = []error{errors.New("still broken")}
+					worker.mu.Unlock()
+					go worker.exit(errors.New("crashed"))
+				}
+			case EventGaveUp:
+				gaveUp = true
+			}
+		}),
+	)
+	require.NoError(t, c.Register(worker))
+
+	_, err := c.Run()
+	require.Error(t, err)
+	assert.True(t, gaveUp)
+	assert.Contains(t, err.Error(), "still broken")
+	assert.Equal(t, []string{"start:worker", "stop:worker", "start:worker", "stop:worker"}, rec.list())
+}
@@ -0,0 +1,65 @@
+package container
+
+import (
+	"time"
+
+	"github.com/yanking/micro-zero/pkg/log"
+)
+
+// EventType identifies a component lifecycle event.
+type EventType string
+
+const (
+	// EventStarted is emitted when a component has started and is ready.
+	EventStarted EventType = "started"
+	// EventStartFailed is emitted when a component failed to start or to
+	// become ready.
+	EventStartFailed EventType = "start-failed"
+	// EventExited is emitted when the background work of a running component
+	// exits while the application is still running.
+	EventExited EventType = "exited"
+	// EventRestarting is emitted before every restart attempt.
+	EventRestarting EventType = "restarting"
+	// EventGaveUp is emitted when the supervision policy gives up on a
+	// component.
+	EventGaveUp EventType = "gave-up"
+	// EventStopped is emitted when a component has been stopped during the
+	// application shutdown.
+	EventStopped EventType = "stopped"
+)
+
+// Event describes a component lifecycle event.
+type Event struct {
+	Type      EventType
+	Component string
+	// Attempt is the restart attempt number of EventRestarting events.
+	Attempt int
+	// Backoff is the delay before the restart attempt of EventRestarting events.
+	Backoff time.Duration
+	// Duration is the time Start or Stop took for EventStarted,
+	// EventStartFailed and EventStopped events.
+	Duration time.Duration
+	// Err is the error that caused the event, if any.
+	Err error
+}
+
+// EventHandler is called synchronously for every component lifecycle event.
+// Handlers must be fast and must not call back into the container's
+// lifecycle methods.
+type EventHandler func(Event)
+
+// WithEventHandler registers a handler for component lifecycle events, e.g. to
+// record metrics. It may be given multiple times.
+func WithEventHandler(h EventHandler) Option {
+	return func(c *Container) {
+		c.eventHandlers = append(c.eventHandlers, h)
+	}
+}
+
+// emit dispatches the event to all registered handlers.
+func (c *Container) emit(e Event) {
+	log.Debugw("container: component event", "component", e.Component, "event", e.Type, "attempt", e.Attempt, "err", e.Err)
+	for _, h := range c.eventHandlers {
+		h(e)
+	}
+}
@@ -0,0 +1,126 @@
+package container
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yanking/micro-zero/pkg/contract"
+)
+
+// dependenciesOf returns the names of the components the given component
+// depends on, or nil if it does not declare any.
+func dependenciesOf(cp contract.Component) []string {
+	if d, ok := cp.(contract.DependentComponent); ok {
+		return d.DependsOn()
+	}
+	return nil
+}
+
+// topologicalWaves groups components into start waves. Every component in a
+// wave only depends on components of earlier waves, so the components of one
+// wave can be started concurrently once the previous wave is up.
+//
+// When strict is true, a dependency on a component that is not in the list is
+// reported as an error. Otherwise such dependencies are ignored, which is what
+// Register needs since dependencies may be registered later.
+func topologicalWaves(components []contract.Component, strict bool) ([][]contract.Component, error) {
+	index := make(map[string]int, len(components))
+	for i, cp := range components {
+		index[cp.Name()] = i
+	}
+
+	// indegree counts unresolved dependencies, dependents maps a component
+	// to the components that depend on it.
+	indegree := make([]int, len(components))
+	dependents := make([][]int, len(components))
+	for i, cp := range components {
+		for _, dep := range dependenciesOf(cp) {
+			j, ok := index[dep]
+			if !ok {
+				if strict {
+					return nil, fmt.Errorf("component %s depends on unregistered component %s", cp.Name(), dep)
+				}
+				continue
+			}
+			if i == j {
+				return nil, fmt.Errorf("component %s depends on itself", cp.Name())
+			}
+			indegree[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+
+	var waves [][]contract.Component
+	var current []int
+	for i := range components {
+		if indegree[i] == 0 {
+			current = append(current, i)
+		}
+	}
+
+	resolved := 0
+	for len(current) > 0 {
+		wave := make([]contract.Component, 0, len(current))
+		var next []int
+		for _, i := range current {
+			wave = append(wave, components[i])
+			for _, d := range dependents[i] {
+				indegree[d]--
+				if indegree[d] == 0 {
+					next = append(next, d)
+				}
+			}
+		}
+		resolved += len(current)
+		waves = append(waves, wave)
+		// Keep the registration order of the components inside a wave.
+		sort.Ints(next)
+		current = next
+	}
+
+	if resolved != len(components) {
+		return nil, fmt.Errorf("dependency cycle detected: %s", describeCycle(components, index, indegree))
+	}
+
+	return waves, nil
+}
+
+// describeCycle walks the unresolved components and returns a human readable
+// description of one dependency cycle, e.g. "a -> b -> a".
+func describeCycle(components []contract.Component, index map[string]int, indegree []int) string {
+	start := -1
+	for i := range components {
+		if indegree[i] > 0 {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return "unknown"
+	}
+
+	// Every unresolved component has at least one unresolved dependency, so
+	// following them must eventually revisit a component.
+	seen := make(map[int]int)
+	var path []string
+	for cur := start; ; {
+		if pos, ok := seen[cur]; ok {
+			return strings.Join(append(path[pos:], components[cur].Name()), " -> ")
+		}
+		seen[cur] = len(path)
+		path = append(path, components[cur].Name())
+
+		next := -1
+		for _, dep := range dependenciesOf(components[cur]) {
+			if j, ok := index[dep]; ok && indegree[j] > 0 {
+				next = j
+				break
+			}
+		}
+		if next < 0 {
+			return strings.Join(path, " -> ")
+		}
+		cur = next
+	}
+}
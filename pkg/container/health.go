@@ -0,0 +1,68 @@
+package container
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/yanking/micro-zero/pkg/contract"
+	"github.com/yanking/micro-zero/pkg/healthz"
+)
+
+// LivezChecks returns the checks of the liveness endpoint. Liveness only
+// reports whether the process is able to serve at all and deliberately does
+// not depend on the components' backends, so that an outage of a backend
+// does not get the application restarted.
+func (c *Container) LivezChecks() []healthz.Checker {
+	return []healthz.Checker{healthz.PingHealthz}
+}
+
+// ReadyzChecks returns the checks of the readiness endpoint: the startup
+// state of the container and the health checks of all registered components
+// implementing contract.HealthChecker.
+func (c *Container) ReadyzChecks() []healthz.Checker {
+	checks := []healthz.Checker{healthz.PingHealthz, healthz.NamedCheck("components", c.checkComponents)}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, cp := range c.components {
+		if hc, ok := cp.(contract.HealthChecker); ok {
+			checks = append(checks, healthz.NamedCheck(cp.Name(), func(r *http.Request) error {
+				return hc.HealthCheck(r.Context())
+			}))
+		}
+	}
+	return checks
+}
+
+// InstallHealthz registers the /livez and /readyz endpoints and the /healthz
+// endpoint at healthzPath on mux. /healthz runs the same checks as /readyz.
+func (c *Container) InstallHealthz(mux *http.ServeMux, healthzPath string) {
+	healthz.InstallLivezHandler(mux, c.LivezChecks)
+	healthz.InstallReadyzHandler(mux, c.ReadyzChecks)
+	if healthzPath == "" {
+		healthzPath = "/healthz"
+	}
+	healthz.InstallPathHandler(mux, healthzPath, c.ReadyzChecks)
+}
+
+// checkComponents fails while the application is starting or stopping, or
+// while a critical component is not ready.
+func (c *Container) checkComponents(_ *http.Request) error {
+	if c.Ready() {
+		return nil
+	}
+
+	var notReady []string
+	for name, s := range c.States() {
+		if s != StateReady {
+			notReady = append(notReady, fmt.Sprintf("%s=%s", name, s))
+		}
+	}
+	sort.Strings(notReady)
+	if len(notReady) == 0 {
+		return fmt.Errorf("application is not running")
+	}
+	return fmt.Errorf("components not ready: %s", strings.Join(notReady, ", "))
+}
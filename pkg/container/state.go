@@ -0,0 +1,75 @@
+package container
+
+import "github.com/yanking/micro-zero/pkg/contract"
+
+// State describes the lifecycle state of a registered component.
+type State string
+
+const (
+	// StatePending means the component is registered but not started yet.
+	StatePending State = "pending"
+	// StateStarting means the component is starting or waiting to become ready.
+	StateStarting State = "starting"
+	// StateReady means the component has started and is ready to serve.
+	StateReady State = "ready"
+	// StateFailed means the component failed to start or to become ready.
+	StateFailed State = "failed"
+	// StateStopping means the component is being stopped.
+	StateStopping State = "stopping"
+	// StateStopped means the component has been stopped.
+	StateStopped State = "stopped"
+)
+
+// setState records the lifecycle state of the named component.
+func (c *Container) setState(name string, s State) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.states[name] = s
+}
+
+// State returns the lifecycle state of the named component. The boolean is
+// false if no component with that name is registered.
+func (c *Container) State(name string) (State, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	s, ok := c.states[name]
+	return s, ok
+}
+
+// States returns a snapshot of the lifecycle states of all registered
+// components, keyed by component name.
+func (c *Container) States() map[string]State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	states := make(map[string]State, len(c.states))
+	for name, s := range c.states {
+		states[name] = s
+	}
+	return states
+}
+
+// Components returns the registered components in registration order.
+func (c *Container) Components() []contract.Component {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]contract.Component(nil), c.components...)
+}
+
+// Ready reports whether the application has finished starting up and every
+// registered critical component is ready to serve. Non-critical components
+// do not affect the readiness of the application.
+func (c *Container) Ready() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.running {
+		return false
+	}
+	for name, s := range c.states {
+		if s != StateReady && c.policyFor(name).Critical {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,262 @@
+package container
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/yanking/micro-zero/pkg/contract"
+	"github.com/yanking/micro-zero/pkg/log"
+)
+
+// RestartPolicy decides whether a failed or exited component is restarted.
+type RestartPolicy string
+
+const (
+	// RestartNever never restarts the component.
+	RestartNever RestartPolicy = "never"
+	// RestartOnFailure restarts the component when it fails to start or its
+	// background work exits with an error, up to MaxRetries times.
+	RestartOnFailure RestartPolicy = "on-failure"
+	// RestartAlways restarts the component whenever it fails or exits.
+	RestartAlways RestartPolicy = "always"
+)
+
+const (
+	defaultInitialBackoff = time.Second
+	defaultMaxBackoff     = 30 * time.Second
+)
+
+// SupervisionPolicy describes how the container supervises a component.
+type SupervisionPolicy struct {
+	// Restart decides whether the component is restarted.
+	Restart RestartPolicy `json:"restart" mapstructure:"restart"`
+	// MaxRetries limits the consecutive restart attempts of RestartOnFailure.
+	// Zero means unlimited.
+	MaxRetries int `json:"max-retries" mapstructure:"max-retries"`
+	// InitialBackoff is the delay before the first restart attempt. It doubles
+	// with every further attempt up to MaxBackoff.
+	InitialBackoff time.Duration `json:"initial-backoff" mapstructure:"initial-backoff"`
+	// MaxBackoff caps the delay between restart attempts.
+	MaxBackoff time.Duration `json:"max-backoff" mapstructure:"max-backoff"`
+	// Critical components tear the application down once they fail for good.
+	// Failures of non-critical components are logged and the application
+	// keeps serving.
+	Critical bool `json:"critical" mapstructure:"critical"`
+}
+
+// DefaultSupervisionPolicy returns the policy applied to components without a
+// specific one: no restarts, and any failure shuts the application down.
+func DefaultSupervisionPolicy() SupervisionPolicy {
+	return SupervisionPolicy{
+		Restart:        RestartNever,
+		InitialBackoff: defaultInitialBackoff,
+		MaxBackoff:     defaultMaxBackoff,
+		Critical:       true,
+	}
+}
+
+// Validate verifies the supervision policy.
+func (p SupervisionPolicy) Validate() error {
+	switch p.Restart {
+	case RestartNever, RestartOnFailure, RestartAlways:
+	default:
+		return fmt.Errorf("invalid restart policy %q: must be one of %s, %s, %s", p.Restart, RestartNever, RestartOnFailure, RestartAlways)
+	}
+	if p.MaxRetries < 0 {
+		return fmt.Errorf("max-retries must not be negative")
+	}
+	return nil
+}
+
+// allowsRestart reports whether attempt number attempt (starting at 1) may be
+// made after the component failed with err, or exited cleanly if err is nil.
+func (p SupervisionPolicy) allowsRestart(err error, attempt int) bool {
+	switch p.Restart {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return err != nil && (p.MaxRetries <= 0 || attempt <= p.MaxRetries)
+	default:
+		return false
+	}
+}
+
+// backoff returns the delay before restart attempt number attempt.
+func (p SupervisionPolicy) backoff(attempt int) time.Duration {
+	d, limit := p.InitialBackoff, p.MaxBackoff
+	if d <= 0 {
+		d = defaultInitialBackoff
+	}
+	if limit <= 0 {
+		limit = defaultMaxBackoff
+	}
+	for i := 1; i < attempt && d < limit; i++ {
+		d *= 2
+	}
+	return min(d, limit)
+}
+
+// WithDefaultSupervision sets the supervision policy of components without a
+// specific policy.
+func WithDefaultSupervision(p SupervisionPolicy) Option {
+	return func(c *Container) {
+		c.defaultPolicy = p
+	}
+}
+
+// WithSupervision sets the supervision policy of the named component. Names
+// are matched case-insensitively, since configuration keys are lower-cased.
+func WithSupervision(name string, p SupervisionPolicy) Option {
+	return func(c *Container) {
+		c.policies[nameKey(name)] = p
+	}
+}
+
+// policyFor returns the supervision policy of the named component.
+func (c *Container) policyFor(name string) SupervisionPolicy {
+	if p, ok := c.policies[nameKey(name)]; ok {
+		return p
+	}
+	return c.defaultPolicy
+}
+
+// nameKey returns the key component specific settings are stored under.
+// Names are matched case-insensitively, since configuration keys are
+// lower-cased.
+func nameKey(name string) string {
+	return strings.ToLower(name)
+}
+
+// startSupervised starts a component during application startup. A critical
+// component is retried in place according to its policy and its final error
+// is returned. A non-critical component never fails the startup: it is
+// restarted in the background if its policy allows.
+func (c *Container) startSupervised(appCtx, startupCtx context.Context, cp contract.Component) error {
+	err := c.startAndWatch(appCtx, startupCtx, cp)
+	if err == nil {
+		return nil
+	}
+
+	policy := c.policyFor(cp.Name())
+	if policy.Critical {
+		return c.restart(appCtx, startupCtx, cp, err)
+	}
+
+	log.Warnw("container: non-critical component failed to start, application keeps running",
+		"component", cp.Name(), "err", err)
+	c.supervisors.Add(1)
+	go func() {
+		defer c.supervisors.Done()
+		_ = c.restart(appCtx, appCtx, cp, err)
+	}()
+	return nil
+}
+
+// startAndWatch starts a component once and, on success, watches its
+// background work for exits.
+func (c *Container) startAndWatch(appCtx, ctx context.Context, cp contract.Component) error {
+	begin := time.Now()
+	if err := c.startComponent(appCtx, ctx, cp); err != nil {
+		c.setState(cp.Name(), StateFailed)
+		c.emit(Event{Type: EventStartFailed, Component: cp.Name(), Duration: time.Since(begin), Err: err})
+		return err
+	}
+
+	c.setState(cp.Name(), StateReady)
+	c.emit(Event{Type: EventStarted, Component: cp.Name(), Duration: time.Since(begin)})
+	c.watch(appCtx, cp)
+	return nil
+}
+
+// watch supervises the background work of a started component implementing
+// contract.ExitNotifier until the application stops.
+func (c *Container) watch(appCtx context.Context, cp contract.Component) {
+	en, ok := cp.(contract.ExitNotifier)
+	if !ok {
+		return
+	}
+	exited := en.Exited()
+
+	c.supervisors.Add(1)
+	go func() {
+		defer c.supervisors.Done()
+
+		var err error
+		select {
+		case err = <-exited:
+		case <-appCtx.Done():
+			return
+		}
+		if appCtx.Err() != nil {
+			return
+		}
+
+		c.emit(Event{Type: EventExited, Component: cp.Name(), Err: err})
+		if err != nil {
+			c.setState(cp.Name(), StateFailed)
+			log.Errorf("container: component %s exited unexpectedly: %v", cp.Name(), err)
+		} else {
+			c.setState(cp.Name(), StateStopped)
+			log.Infof("container: component %s exited", cp.Name())
+		}
+
+		if err := c.restart(appCtx, appCtx, cp, err); err != nil && c.policyFor(cp.Name()).Critical {
+			c.stopApp(fmt.Errorf("critical component %s failed: %w", cp.Name(), err))
+		}
+	}()
+}
+
+// restart tries to bring a failed or exited component back according to its
+// supervision policy. It returns nil once the component runs again, or the
+// last error once the policy gives up or ctx is done. lastErr is nil if the
+// component exited cleanly.
+func (c *Container) restart(appCtx, ctx context.Context, cp contract.Component, lastErr error) error {
+	policy := c.policyFor(cp.Name())
+	for attempt := 1; policy.allowsRestart(lastErr, attempt); attempt++ {
+		backoff := policy.backoff(attempt)
+		c.emit(Event{Type: EventRestarting, Component: cp.Name(), Attempt: attempt, Backoff: backoff, Err: lastErr})
+		log.Warnw("container: restarting component", "component", cp.Name(), "attempt", attempt, "backoff", backoff, "err", lastErr)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return lastErr
+		}
+
+		// Release whatever the previous run left behind before starting again.
+		c.stopForRestart(cp)
+
+		attemptCtx, cancel := context.WithTimeout(ctx, c.startupTimeout)
+		err := c.startAndWatch(appCtx, attemptCtx, cp)
+		cancel()
+		if err == nil {
+			log.Infof("container: component %s restarted after %d attempt(s)", cp.Name(), attempt)
+			return nil
+		}
+		lastErr = err
+	}
+
+	if lastErr != nil && policy.Restart != RestartNever {
+		c.emit(Event{Type: EventGaveUp, Component: cp.Name(), Err: lastErr})
+		log.Errorf("container: giving up on component %s: %v", cp.Name(), lastErr)
+	}
+	return lastErr
+}
+
+// stopForRestart stops a component before it is restarted. Errors are only
+// logged, the component failed already.
+func (c *Container) stopForRestart(cp contract.Component) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.shutdownOverallTimeout)
+	defer cancel()
+	if timeout := c.timeouts.stopTimeout(cp); timeout > 0 {
+		var cancelStop context.CancelFunc
+		ctx, cancelStop = context.WithTimeout(ctx, timeout)
+		defer cancelStop()
+	}
+
+	if err := callWithContext(ctx, func() error { return cp.Stop(ctx) }); err != nil {
+		log.Debugw("container: error stopping component before restart", "component", cp.Name(), "err", err)
+	}
+}
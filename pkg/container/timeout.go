@@ -0,0 +1,80 @@
+package container
+
+import (
+	"time"
+
+	"github.com/yanking/micro-zero/pkg/contract"
+)
+
+// componentTimeouts holds the start and stop timeouts applied to components.
+// A timeout configured for a component by name takes precedence over the
+// timeout the component reports itself, which takes precedence over the
+// container default. Zero means no component specific limit.
+type componentTimeouts struct {
+	defaultStart time.Duration
+	defaultStop  time.Duration
+	start        map[string]time.Duration
+	stop         map[string]time.Duration
+}
+
+func newComponentTimeouts() componentTimeouts {
+	return componentTimeouts{
+		start: make(map[string]time.Duration),
+		stop:  make(map[string]time.Duration),
+	}
+}
+
+// startTimeout returns the start timeout of the given component.
+func (t componentTimeouts) startTimeout(cp contract.Component) time.Duration {
+	if d, ok := t.start[nameKey(cp.Name())]; ok {
+		return d
+	}
+	if p, ok := cp.(contract.StartTimeoutProvider); ok {
+		return p.StartTimeout()
+	}
+	return t.defaultStart
+}
+
+// stopTimeout returns the stop timeout of the given component.
+func (t componentTimeouts) stopTimeout(cp contract.Component) time.Duration {
+	if d, ok := t.stop[nameKey(cp.Name())]; ok {
+		return d
+	}
+	if p, ok := cp.(contract.StopTimeoutProvider); ok {
+		return p.StopTimeout()
+	}
+	return t.defaultStop
+}
+
+// WithStartTimeout sets the default time a single component may take to start
+// and become ready. It applies to components without a specific start timeout.
+func WithStartTimeout(t time.Duration) Option {
+	return func(c *Container) {
+		c.timeouts.defaultStart = t
+	}
+}
+
+// WithStopTimeout sets the default time a single component may take to stop.
+// It applies to components without a specific stop timeout, so that one slow
+// component cannot use up the whole shutdown budget.
+func WithStopTimeout(t time.Duration) Option {
+	return func(c *Container) {
+		c.timeouts.defaultStop = t
+	}
+}
+
+// WithComponentStartTimeout sets the start timeout of the named component.
+// Names are matched case-insensitively, like in WithSupervision.
+func WithComponentStartTimeout(name string, t time.Duration) Option {
+	return func(c *Container) {
+		c.timeouts.start[nameKey(name)] = t
+	}
+}
+
+// WithComponentStopTimeout sets the stop timeout of the named component.
+// Names are matched case-insensitively, like in WithSupervision.
+func WithComponentStopTimeout(name string, t time.Duration) Option {
+	return func(c *Container) {
+		c.timeouts.stop[nameKey(name)] = t
+	}
+}
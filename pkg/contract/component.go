@@ -1,6 +1,9 @@
 package contract
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // Component defines the interface for a manageable application component
 // that has a distinct start and stop container.
@@ -9,3 +12,56 @@ type Component interface {
 	Stop(ctx context.Context) error  // Stops the component gracefully.
 	Name() string                    // Returns the name of the component for logging.
 }
+
+// DependentComponent is an optional interface for components that require
+// other components to be started before them. The container starts a
+// component only after all of its dependencies and stops it before them.
+type DependentComponent interface {
+	// DependsOn returns the names of the components this component depends on.
+	DependsOn() []string
+}
+
+// ReadinessChecker is an optional interface for components whose Start
+// returns before they are able to serve, e.g. servers that bind and serve in
+// the background. The container waits for Ready before it starts the
+// components depending on them.
+type ReadinessChecker interface {
+	// Ready blocks until the component is ready to serve or ctx is done.
+	// It returns an error if the component failed to become ready.
+	Ready(ctx context.Context) error
+}
+
+// StartTimeoutProvider is an optional interface for components that need a
+// start timeout different from the container default. The timeout covers
+// both Start and, if implemented, Ready.
+type StartTimeoutProvider interface {
+	// StartTimeout returns the maximum duration the component may take to
+	// start. Zero means no component specific limit.
+	StartTimeout() time.Duration
+}
+
+// StopTimeoutProvider is an optional interface for components that need a
+// stop timeout different from the container default.
+type StopTimeoutProvider interface {
+	// StopTimeout returns the maximum duration the component may take to
+	// stop. Zero means no component specific limit.
+	StopTimeout() time.Duration
+}
+
+// ExitNotifier is an optional interface for components whose work keeps
+// running in the background after Start returns, e.g. consumers or servers.
+// The container watches the returned channel to supervise the component.
+// Components that may be restarted must support Start after Stop.
+type ExitNotifier interface {
+	// Exited returns a channel that receives the result of the background
+	// work of the current run when it exits; nil means a clean exit.
+	Exited() <-chan error
+}
+
+// HealthChecker is an optional interface for components that can report
+// their health, e.g. by pinging the backend they are connected to. The
+// container exposes the checks on its health endpoints.
+type HealthChecker interface {
+	// HealthCheck returns nil if the component is healthy.
+	HealthCheck(ctx context.Context) error
+}
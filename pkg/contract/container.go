@@ -1,7 +1,51 @@
 package contract
 
+import (
+	"fmt"
+	"time"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
 type IContainer interface {
 	Name() string
-	Run() error
-	Register(Component)
+	Run() (*ShutdownReport, error)
+	Register(Component) error
+}
+
+// ComponentStopReport describes how stopping a single component went.
+type ComponentStopReport struct {
+	// Name is the name of the component.
+	Name string `json:"name"`
+	// Duration is the time the component's Stop method took.
+	Duration time.Duration `json:"duration"`
+	// Err is the error returned by Stop, nil if the component stopped cleanly.
+	Err error `json:"-"`
+}
+
+// ShutdownReport summarises the graceful shutdown of a container.
+type ShutdownReport struct {
+	// Cause is the reason of the shutdown. It is nil for a shutdown triggered
+	// by a signal.
+	Cause error `json:"-"`
+	// Duration is the time the whole shutdown sequence took.
+	Duration time.Duration `json:"duration"`
+	// Components lists the stopped components in stop order.
+	Components []ComponentStopReport `json:"components"`
+}
+
+// Err aggregates the errors of all components that failed to stop cleanly.
+// It returns nil if every component stopped without error.
+func (r *ShutdownReport) Err() error {
+	if r == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, cr := range r.Components {
+		if cr.Err != nil {
+			errs = append(errs, fmt.Errorf("component %s: %w", cr.Name, cr.Err))
+		}
+	}
+	return utilerrors.NewAggregate(errs)
 }
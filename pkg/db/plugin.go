@@ -7,62 +7,214 @@
 package db
 
 import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"strconv"
 	"time"
 
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
 	"gorm.io/gorm"
-	"k8s.io/klog/v2"
+
+	"github.com/yanking/micro-zero/pkg/metrics"
+	"github.com/yanking/micro-zero/pkg/tracing"
 )
 
 const (
-	callBackBeforeName = "core:before"
-	callBackAfterName  = "core:after"
-	startTime          = "_start_time"
+	startTimeKey = "observability:start_time"
+	spanKey      = "observability:span"
+	spanCtxKey   = "observability:span_ctx"
+	parentCtxKey = "observability:parent_ctx"
 )
 
-// TracePlugin defines gorm plugin used to trace sql.
-type TracePlugin struct{}
+// statementKey is the attribute holding the SQL statement. The key predates
+// db.query.text but is still the one highlighted by Jaeger and most backends.
+const statementKey = attribute.Key("db.statement")
+
+// MetricsName returns the db label of the metrics of a database, e.g. "mysql/onex".
+// The system is included so that databases with the same name in different systems
+// are told apart.
+func MetricsName(system, database string) string {
+	return system + "/" + database
+}
 
-// Name returns the name of trace plugin.
-func (op *TracePlugin) Name() string {
-	return "tracePlugin"
+// PluginOptions defines what the observability plugin records.
+type PluginOptions struct {
+	// Name is the db label of the metrics, see MetricsName.
+	Name string
+	// Metrics enables the latency, error and rows affected metrics per operation and table.
+	Metrics bool
+	// Tracing enables a client span per statement. Only the SQL with placeholders
+	// is recorded, the parameters may contain sensitive data.
+	Tracing bool
 }
 
-// Initialize the trace plugin.
-func (op *TracePlugin) Initialize(db *gorm.DB) (err error) {
-	// 开始前
-	_ = db.Callback().Create().Before("gorm:before_create").Register(callBackBeforeName, before)
-	_ = db.Callback().Query().Before("gorm:query").Register(callBackBeforeName, before)
-	_ = db.Callback().Delete().Before("gorm:before_delete").Register(callBackBeforeName, before)
-	_ = db.Callback().Update().Before("gorm:setup_reflect_value").Register(callBackBeforeName, before)
-	_ = db.Callback().Row().Before("gorm:row").Register(callBackBeforeName, before)
-	_ = db.Callback().Raw().Before("gorm:raw").Register(callBackBeforeName, before)
-
-	// 结束后
-	_ = db.Callback().Create().After("gorm:after_create").Register(callBackAfterName, after)
-	_ = db.Callback().Query().After("gorm:after_query").Register(callBackAfterName, after)
-	_ = db.Callback().Delete().After("gorm:after_delete").Register(callBackAfterName, after)
-	_ = db.Callback().Update().After("gorm:after_update").Register(callBackAfterName, after)
-	_ = db.Callback().Row().After("gorm:row").Register(callBackAfterName, after)
-	_ = db.Callback().Raw().After("gorm:raw").Register(callBackAfterName, after)
-
-	return
+// ObservabilityPlugin defines gorm plugin recording metrics and spans of the statements.
+type ObservabilityPlugin struct {
+	opts PluginOptions
 }
 
-var _ gorm.Plugin = &TracePlugin{}
+var _ gorm.Plugin = (*ObservabilityPlugin)(nil)
 
-func before(db *gorm.DB) {
-	db.InstanceSet(startTime, time.Now())
+// NewObservabilityPlugin creates an observability plugin with the given options.
+func NewObservabilityPlugin(opts PluginOptions) *ObservabilityPlugin {
+	return &ObservabilityPlugin{opts: opts}
 }
 
-func after(db *gorm.DB) {
-	_ts, isExist := db.InstanceGet(startTime)
-	if !isExist {
-		return
+// Name returns the name of observability plugin.
+func (p *ObservabilityPlugin) Name() string {
+	return "observabilityPlugin"
+}
+
+// Initialize registers the callbacks around the statements.
+func (p *ObservabilityPlugin) Initialize(db *gorm.DB) error {
+	// 只覆盖执行 SQL 的回调，不包括钩子和事务
+	cb := db.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("observability:before_create", p.before("insert")),
+		cb.Create().After("gorm:create").Register("observability:after_create", p.after("insert")),
+		cb.Query().Before("gorm:query").Register("observability:before_query", p.before("select")),
+		cb.Query().After("gorm:query").Register("observability:after_query", p.after("select")),
+		cb.Update().Before("gorm:update").Register("observability:before_update", p.before("update")),
+		cb.Update().After("gorm:update").Register("observability:after_update", p.after("update")),
+		cb.Delete().Before("gorm:delete").Register("observability:before_delete", p.before("delete")),
+		cb.Delete().After("gorm:delete").Register("observability:after_delete", p.after("delete")),
+		cb.Row().Before("gorm:row").Register("observability:before_row", p.before("row")),
+		cb.Row().After("gorm:row").Register("observability:after_row", p.after("row")),
+		cb.Raw().Before("gorm:raw").Register("observability:before_raw", p.before("raw")),
+		cb.Raw().After("gorm:raw").Register("observability:after_raw", p.after("raw")),
+	)
+}
+
+func (p *ObservabilityPlugin) before(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		db.InstanceSet(startTimeKey, time.Now())
+		if !p.opts.Tracing {
+			return
+		}
+
+		parent := db.Statement.Context
+		if parent == nil {
+			parent = context.Background()
+		}
+		// 同一个 Statement 再次执行时，它的 context 仍带有上一条语句的 span，以调用方原来的 context 作为父级
+		if spanCtx, ok := db.InstanceGet(spanCtxKey); ok && spanCtx == parent {
+			if v, ok := db.InstanceGet(parentCtxKey); ok {
+				parent = v.(context.Context)
+			}
+		}
+		ctx, span := tracing.Tracer().Start(parent, op, trace.WithSpanKind(trace.SpanKindClient))
+		// GORM 在所有回调执行完之后才以 Statement.Context 记录 SQL 日志，
+		// 因此语句结束后 context 仍保留本次的 span，使 SQL 日志与 span 关联
+		db.Statement.Context = ctx
+		db.InstanceSet(spanKey, span)
+		db.InstanceSet(spanCtxKey, ctx)
+		db.InstanceSet(parentCtxKey, parent)
+	}
+}
+
+func (p *ObservabilityPlugin) after(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		v, ok := db.InstanceGet(startTimeKey)
+		if !ok {
+			return
+		}
+		begin, _ := v.(time.Time)
+		elapsed := time.Since(begin)
+
+		table := db.Statement.Table
+		err := db.Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// 记录不存在是正常的查询结果，不计为错误
+			err = nil
+		}
+
+		if p.opts.Metrics {
+			metrics.ObserveDBOperation(p.opts.Name, op, table, db.RowsAffected, errorType(err), elapsed)
+		}
+		if p.opts.Tracing {
+			p.endSpan(db, op, table, err)
+		}
 	}
+}
 
-	ts, ok := _ts.(time.Time)
+func (p *ObservabilityPlugin) endSpan(db *gorm.DB, op, table string, err error) {
+	v, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
 	if !ok {
 		return
 	}
-	klog.V(4).Infof("sql cost time: %fs", time.Since(ts).Seconds())
+	attrs := []attribute.KeyValue{dbSystem(db), semconv.DBOperationName(op)}
+	if table != "" {
+		span.SetName(op + " " + table)
+		attrs = append(attrs, semconv.DBCollectionName(table))
+	}
+	if sql := db.Statement.SQL.String(); sql != "" {
+		attrs = append(attrs, statementKey.String(sql))
+	}
+	if db.RowsAffected >= 0 {
+		attrs = append(attrs, attribute.Int64("db.rows_affected", db.RowsAffected))
+	}
+	span.SetAttributes(attrs...)
+	if err != nil {
+		span.SetAttributes(semconv.ErrorTypeKey.String(errorType(err)))
+		tracing.RecordError(span, err)
+	}
+	span.End()
+}
+
+// errorType classifies err for the error_type label, returns an empty string if err is nil.
+// Errors of the drivers are classified by the MySQL error number or the PostgreSQL SQLSTATE code.
+func errorType(err error) string {
+	var (
+		mysqlErr *gomysql.MySQLError
+		pgErr    *pgconn.PgError
+		netErr   net.Error
+	)
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, driver.ErrBadConn):
+		return "bad_connection"
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return "duplicated_key"
+	case errors.Is(err, gorm.ErrForeignKeyViolated):
+		return "foreign_key_violated"
+	case errors.Is(err, gorm.ErrCheckConstraintViolated):
+		return "check_constraint_violated"
+	case errors.As(err, &mysqlErr):
+		return "mysql_" + strconv.Itoa(int(mysqlErr.Number))
+	case errors.As(err, &pgErr):
+		return "postgresql_" + pgErr.Code
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return "timeout"
+		}
+		return "network"
+	default:
+		return "other"
+	}
+}
+
+func dbSystem(db *gorm.DB) attribute.KeyValue {
+	switch name := db.Dialector.Name(); name {
+	case "mysql":
+		return semconv.DBSystemNameMySQL
+	case "postgres":
+		return semconv.DBSystemNamePostgreSQL
+	default:
+		return semconv.DBSystemNameKey.String(name)
+	}
 }
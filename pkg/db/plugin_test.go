@@ -0,0 +1,127 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+
+	"github.com/yanking/micro-zero/pkg/log"
+	"github.com/yanking/micro-zero/pkg/metrics"
+)
+
+type user struct {
+	ID   uint
+	Name string
+}
+
+func TestObservabilityPlugin(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder)))
+	metrics.Register()
+
+	// DryRun 只生成 SQL 而不执行，不需要连接数据库
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "root@tcp(127.0.0.1:0)/test", SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewObservabilityPlugin(PluginOptions{Name: "test", Metrics: true, Tracing: true})))
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "handler")
+	tx := db.WithContext(ctx).Where("name = ?", "colin").Find(&[]user{})
+	require.NoError(t, tx.Error)
+	parent.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	span := spans[0]
+	assert.Equal(t, "select users", span.Name())
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+	assert.Equal(t, span.SpanContext(), trace.SpanContextFromContext(tx.Statement.Context), "the statement keeps its span for the SQL log")
+	attrs := map[string]string{}
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	assert.Equal(t, "SELECT * FROM `users` WHERE name = ?", attrs["db.statement"])
+	assert.Equal(t, "mysql", attrs["db.system.name"])
+	assert.Equal(t, "users", attrs["db.collection.name"])
+
+	rec := httptest.NewRecorder()
+	metrics.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), `db_client_operation_duration_seconds_count{db="test",operation="select",table="users"} 1`)
+	assert.Contains(t, string(body), `db_client_rows_affected_count{db="test",operation="select",table="users"} 1`)
+}
+
+func TestSQLLogHasStatementSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder)))
+	metrics.Register()
+
+	file := filepath.Join(t.TempDir(), "app.log")
+	opts := log.NewOptions()
+	opts.Format = "json"
+	opts.OutputPaths = []string{file}
+	opts.EnableTraceContext = true
+	require.NoError(t, log.Init(opts))
+	t.Cleanup(func() { log.Init(log.NewOptions()) })
+
+	gormOpts := log.NewGormOptions()
+	gormOpts.Level = "info"
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "root@tcp(127.0.0.1:0)/test", SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: log.NewGormLogger(gormOpts)})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewObservabilityPlugin(PluginOptions{Name: "test", Tracing: true})))
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "handler")
+	tx := db.WithContext(ctx).Find(&[]user{})
+	require.NoError(t, tx.Error)
+	// 同一个 Statement 再次执行时，新的 span 仍然以调用方的 span 为父级
+	require.NoError(t, tx.Find(&[]user{}).Error)
+	parent.End()
+	log.Sync()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 2)
+	for i, line := range lines {
+		span := spans[i]
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Contains(t, line, "SELECT * FROM `users`")
+		assert.Contains(t, line, `"trace_id":"`+span.SpanContext().TraceID().String()+`"`)
+		assert.Contains(t, line, `"span_id":"`+span.SpanContext().SpanID().String()+`"`)
+	}
+}
+
+func TestErrorType(t *testing.T) {
+	assert.Equal(t, "", errorType(nil))
+	assert.Equal(t, "timeout", errorType(fmt.Errorf("query: %w", context.DeadlineExceeded)))
+	assert.Equal(t, "duplicated_key", errorType(gorm.ErrDuplicatedKey))
+	assert.Equal(t, "mysql_1213", errorType(&gomysql.MySQLError{Number: 1213, Message: "Deadlock found"}))
+	assert.Equal(t, "postgresql_40001", errorType(&pgconn.PgError{Code: "40001"}))
+	assert.Equal(t, "other", errorType(gorm.ErrInvalidData))
+}
+
+func TestMetricsName(t *testing.T) {
+	assert.Equal(t, "mysql/onex", MetricsName("mysql", "onex"))
+	assert.NotEqual(t, MetricsName("mysql", "onex"), MetricsName("postgresql", "onex"))
+}
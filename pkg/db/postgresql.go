@@ -1,7 +1,8 @@
 package db
 
 import (
-	"fmt"
+	"net"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,25 +20,63 @@ type PostgreSQLOptions struct {
 	MaxIdleConnections    int
 	MaxOpenConnections    int
 	MaxConnectionLifeTime time.Duration
+	// SSLMode is one of disable, allow, prefer, require, verify-ca and verify-full.
+	SSLMode string
+	// SSLRootCert is the file of the CA certificates verifying the server certificate.
+	SSLRootCert string
+	// TimeZone is the time zone of the session.
+	TimeZone string
+	// SearchPath is the schema search path of the session, e.g. "app,public".
+	SearchPath string
+	// ApplicationName is reported by the server in pg_stat_activity.
+	ApplicationName string
+	// StatementTimeout aborts statements running longer than the timeout, 0 means no timeout.
+	StatementTimeout time.Duration
 	// +optional
 	Logger logger.Interface
 }
 
 // DSN return DSN from PostgreSQLOptions.
 func (o *PostgreSQLOptions) DSN() string {
-	splited := strings.Split(o.Addr, ":")
-	host, port := splited[0], "5432"
-	if len(splited) > 1 {
-		port = splited[1]
+	host, port, err := net.SplitHostPort(o.Addr)
+	if err != nil {
+		host, port = o.Addr, "5432"
+	}
+
+	params := []string{
+		"user=" + quoteDSNValue(o.Username),
+		"password=" + quoteDSNValue(o.Password),
+		"host=" + quoteDSNValue(host),
+		"port=" + quoteDSNValue(port),
+		"dbname=" + quoteDSNValue(o.Database),
+		"sslmode=" + quoteDSNValue(o.SSLMode),
+	}
+	if o.SSLRootCert != "" {
+		params = append(params, "sslrootcert="+quoteDSNValue(o.SSLRootCert))
+	}
+	if o.TimeZone != "" {
+		params = append(params, "TimeZone="+quoteDSNValue(o.TimeZone))
+	}
+	if o.SearchPath != "" {
+		params = append(params, "search_path="+quoteDSNValue(o.SearchPath))
 	}
+	if o.ApplicationName != "" {
+		params = append(params, "application_name="+quoteDSNValue(o.ApplicationName))
+	}
+	if o.StatementTimeout > 0 {
+		params = append(params, "statement_timeout="+strconv.FormatInt(o.StatementTimeout.Milliseconds(), 10))
+	}
+
+	return strings.Join(params, " ")
+}
 
-	return fmt.Sprintf(`user=%s password=%s host=%s port=%s dbname=%s sslmode=disable TimeZone=Asia/Shanghai`,
-		o.Username,
-		o.Password,
-		host,
-		port,
-		o.Database,
-	)
+// quoteDSNValue quotes v for a key=value connection string when it is empty or contains
+// spaces, quotes or backslashes.
+func quoteDSNValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, ` '\`) {
+		return v
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
 }
 
 // NewPostgreSQL create a new gorm db instance with the given options.
@@ -86,6 +125,9 @@ func setPostgreSQLDefaults(opts *PostgreSQLOptions) {
 	if opts.MaxConnectionLifeTime == 0 {
 		opts.MaxConnectionLifeTime = time.Duration(10) * time.Second
 	}
+	if opts.SSLMode == "" {
+		opts.SSLMode = "disable"
+	}
 	if opts.Logger == nil {
 		opts.Logger = logger.Default
 	}
@@ -0,0 +1,27 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPostgreSQLOptionsDSN(t *testing.T) {
+	opts := &PostgreSQLOptions{
+		Addr:     "127.0.0.1:5433",
+		Username: "postgres",
+		Database: "miniblog",
+		SSLMode:  "disable",
+	}
+	assert.Equal(t, "user=postgres password='' host=127.0.0.1 port=5433 dbname=miniblog sslmode=disable", opts.DSN())
+
+	opts.Addr = "db.local"
+	opts.Password = `it's a \secret`
+	opts.TimeZone = "Asia/Shanghai"
+	opts.SearchPath = "app,public"
+	opts.ApplicationName = "miniblog api"
+	opts.StatementTimeout = 2 * time.Second
+	assert.Equal(t, `user=postgres password='it\'s a \\secret' host=db.local port=5432 dbname=miniblog sslmode=disable `+
+		`TimeZone=Asia/Shanghai search_path=app,public application_name='miniblog api' statement_timeout=2000`, opts.DSN())
+}
@@ -42,6 +42,7 @@ func NewRedis(opts *RedisOptions) (*redis.Client, error) {
 
 	// check redis if is ok
 	if _, err := rdb.Ping(context.Background()).Result(); err != nil {
+		_ = rdb.Close()
 		return nil, err
 	}
 
@@ -0,0 +1,218 @@
+// Package healthz implements Kubernetes style /livez, /readyz and /healthz
+// endpoints. Each endpoint runs a set of named checks and reports their
+// status. Appending ?verbose lists every check with its latency and last
+// error, ?exclude=<name> skips a check, and /<path>/<name> runs a single check.
+package healthz
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/yanking/micro-zero/pkg/log"
+)
+
+// Checker is a named health check.
+type Checker interface {
+	// Name returns the name of the check, it is used in the verbose output,
+	// in ?exclude and in the path of the single check endpoint.
+	Name() string
+	// Check returns nil if the check passed.
+	Check(req *http.Request) error
+}
+
+// PingHealthz returns true automatically when checked.
+var PingHealthz Checker = ping{}
+
+type ping struct{}
+
+func (ping) Name() string { return "ping" }
+
+func (ping) Check(_ *http.Request) error { return nil }
+
+// NamedCheck returns a checker for the given name and check function.
+func NamedCheck(name string, check func(r *http.Request) error) Checker {
+	return &checkFunc{name: name, check: check}
+}
+
+type checkFunc struct {
+	name  string
+	check func(r *http.Request) error
+}
+
+func (c *checkFunc) Name() string { return c.name }
+
+func (c *checkFunc) Check(r *http.Request) error { return c.check(r) }
+
+// CheckSource returns the checks of an endpoint. It is called on every request
+// so that the set of checks may change while the server is running.
+type CheckSource func() []Checker
+
+// Checks returns a CheckSource serving a fixed set of checks.
+func Checks(checks ...Checker) CheckSource {
+	return func() []Checker { return checks }
+}
+
+// InstallHandler registers the default /healthz endpoint on mux.
+func InstallHandler(mux *http.ServeMux, checks ...Checker) {
+	InstallPathHandler(mux, "/healthz", Checks(checks...))
+}
+
+// InstallLivezHandler registers the /livez endpoint on mux.
+func InstallLivezHandler(mux *http.ServeMux, source CheckSource) {
+	InstallPathHandler(mux, "/livez", source)
+}
+
+// InstallReadyzHandler registers the /readyz endpoint on mux.
+func InstallReadyzHandler(mux *http.ServeMux, source CheckSource) {
+	InstallPathHandler(mux, "/readyz", source)
+}
+
+// InstallPathHandler registers the endpoint at path and the single check
+// endpoints below it on mux.
+func InstallPathHandler(mux *http.ServeMux, path string, source CheckSource) {
+	h := newHandler(path, source)
+	mux.Handle(path, h)
+	mux.Handle(strings.TrimSuffix(path, "/")+"/", h)
+}
+
+// status records the outcome of the previous runs of a check.
+type status struct {
+	latency   time.Duration
+	lastErr   error
+	lastErrAt time.Time
+}
+
+// handler serves one health endpoint.
+type handler struct {
+	path   string
+	source CheckSource
+
+	mu       sync.Mutex
+	statuses map[string]*status
+}
+
+func newHandler(path string, source CheckSource) *handler {
+	return &handler{
+		path:     strings.TrimSuffix(path, "/"),
+		source:   source,
+		statuses: make(map[string]*status),
+	}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	checks := h.source()
+
+	// /<path>/<name> runs a single check.
+	if name := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, h.path), "/"); name != "" {
+		for _, check := range checks {
+			if check.Name() == name {
+				h.serveChecks(w, r, []Checker{check}, nil)
+				return
+			}
+		}
+		http.NotFound(w, r)
+		return
+	}
+
+	excluded := sets.New[string]()
+	for _, values := range r.URL.Query()["exclude"] {
+		for _, v := range strings.Split(values, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				excluded.Insert(v)
+			}
+		}
+	}
+
+	var selected []Checker
+	for _, check := range checks {
+		if excluded.Has(check.Name()) {
+			excluded.Delete(check.Name())
+			continue
+		}
+		selected = append(selected, check)
+	}
+
+	h.serveChecks(w, r, selected, sets.List(excluded))
+}
+
+// serveChecks runs the checks and writes the aggregated result.
+func (h *handler) serveChecks(w http.ResponseWriter, r *http.Request, checks []Checker, unknownExcludes []string) {
+	var (
+		out    bytes.Buffer
+		failed []string
+	)
+
+	for _, check := range checks {
+		begin := time.Now()
+		err := check.Check(r)
+		st := h.record(check.Name(), time.Since(begin), err)
+
+		if err != nil {
+			log.Warnw("healthz check failed", "path", h.path, "check", check.Name(), "err", err)
+			failed = append(failed, check.Name())
+			fmt.Fprintf(&out, "[-]%s failed: %v (latency: %v)\n", check.Name(), err, st.latency)
+			continue
+		}
+		if st.lastErr != nil {
+			fmt.Fprintf(&out, "[+]%s ok (latency: %v, last error at %s: %v)\n",
+				check.Name(), st.latency, st.lastErrAt.Format(time.RFC3339), st.lastErr)
+		} else {
+			fmt.Fprintf(&out, "[+]%s ok (latency: %v)\n", check.Name(), st.latency)
+		}
+	}
+	if len(unknownExcludes) > 0 {
+		fmt.Fprintf(&out, "warn: some health checks cannot be excluded: no matches for %s\n",
+			strings.Join(quote(unknownExcludes), ","))
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(&out, "%s check failed: %s\n", h.path, strings.Join(failed, ","))
+		_, _ = out.WriteTo(w)
+		return
+	}
+
+	if _, verbose := r.URL.Query()["verbose"]; !verbose {
+		_, _ = fmt.Fprint(w, "ok")
+		return
+	}
+	fmt.Fprintf(&out, "%s check passed\n", h.path)
+	_, _ = out.WriteTo(w)
+}
+
+// record stores the result of a check run and returns a copy of its status.
+func (h *handler) record(name string, latency time.Duration, err error) status {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	st, ok := h.statuses[name]
+	if !ok {
+		st = &status{}
+		h.statuses[name] = st
+	}
+	st.latency = latency
+	if err != nil {
+		st.lastErr = err
+		st.lastErrAt = time.Now()
+	}
+	return *st
+}
+
+func quote(names []string) []string {
+	quoted := make([]string, 0, len(names))
+	for _, n := range names {
+		quoted = append(quoted, fmt.Sprintf("%q", n))
+	}
+	return quoted
+}
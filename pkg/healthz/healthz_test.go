@@ -0,0 +1,69 @@
+package healthz
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(mux *http.ServeMux, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestHealthzOK(t *testing.T) {
+	mux := http.NewServeMux()
+	InstallHandler(mux, PingHealthz, NamedCheck("db", func(*http.Request) error { return nil }))
+
+	rec := serve(mux, "/healthz")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok", rec.Body.String())
+
+	rec = serve(mux, "/healthz?verbose")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "[+]ping ok")
+	assert.Contains(t, rec.Body.String(), "[+]db ok")
+	assert.Contains(t, rec.Body.String(), "/healthz check passed")
+}
+
+func TestHealthzFailure(t *testing.T) {
+	mux := http.NewServeMux()
+	InstallHandler(mux, PingHealthz, NamedCheck("db", func(*http.Request) error { return errors.New("connection refused") }))
+
+	rec := serve(mux, "/healthz")
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Contains(t, rec.Body.String(), "[-]db failed: connection refused")
+	assert.Contains(t, rec.Body.String(), "/healthz check failed: db")
+
+	rec = serve(mux, "/healthz?exclude=db")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = serve(mux, "/healthz?exclude=db&exclude=cache&verbose")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `no matches for "cache"`)
+}
+
+func TestHealthzSingleCheck(t *testing.T) {
+	failing := true
+	mux := http.NewServeMux()
+	InstallReadyzHandler(mux, Checks(PingHealthz, NamedCheck("db", func(*http.Request) error {
+		if failing {
+			return errors.New("down")
+		}
+		return nil
+	})))
+
+	assert.Equal(t, http.StatusOK, serve(mux, "/readyz/ping").Code)
+	assert.Equal(t, http.StatusInternalServerError, serve(mux, "/readyz/db").Code)
+	assert.Equal(t, http.StatusNotFound, serve(mux, "/readyz/unknown").Code)
+
+	// A recovered check reports its last error in the verbose output.
+	failing = false
+	rec := serve(mux, "/readyz/db?verbose")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "last error at")
+}
@@ -0,0 +1,65 @@
+package log
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+)
+
+// FieldExtractor 从 context 中提取一个类型化的日志字段，第二个返回值表示字段是否存在.
+type FieldExtractor func(ctx context.Context) (Field, bool)
+
+// fieldsKey 是 WithFields 保存的字段在 context 中的键.
+type fieldsKey struct{}
+
+// WithFields 返回保存了 keyvals 的 context 副本，W 会将这些字段添加到日志中.
+// keyvals 与 Infow 等方法的参数格式相同，多次调用时字段会追加到已有字段之后.
+func WithFields(ctx context.Context, keyvals ...any) context.Context {
+	if len(keyvals) == 0 {
+		return ctx
+	}
+	fields, _ := ctx.Value(fieldsKey{}).([]any)
+	// 复制已有字段，避免不同的 context 共享同一个底层数组
+	merged := make([]any, 0, len(fields)+len(keyvals))
+	merged = append(append(merged, fields...), keyvals...)
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FieldsFromContext 返回 WithFields 保存在 context 中的字段.
+func FieldsFromContext(ctx context.Context) []any {
+	fields, _ := ctx.Value(fieldsKey{}).([]any)
+	return fields
+}
+
+// WithFieldExtractors 添加返回类型化字段的 context 提取逻辑.
+func WithFieldExtractors(extractors ...FieldExtractor) Option {
+	return func(l *zapLogger) {
+		l.fieldExtractors = append(l.fieldExtractors, extractors...)
+	}
+}
+
+// StringExtractor 返回一个提取器，它将 fn 返回的非空字符串作为名为 key 的字段.
+func StringExtractor(key string, fn func(context.Context) string) FieldExtractor {
+	return func(ctx context.Context) (Field, bool) {
+		if val := fn(ctx); val != "" {
+			return zap.String(key, val), true
+		}
+		return Field{}, false
+	}
+}
+
+// contextFields 返回从 context 中提取的所有字段.
+func (l *zapLogger) contextFields(ctx context.Context) []Field {
+	var fields []Field
+	for fieldName, extractor := range l.contextExtractors {
+		if val := extractor(ctx); val != "" {
+			fields = append(fields, zap.String(fieldName, val))
+		}
+	}
+	for _, extractor := range l.fieldExtractors {
+		if field, ok := extractor(ctx); ok {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,50 @@
+package log
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type tenantKey struct{}
+
+func TestInitAppliesOptions(t *testing.T) {
+	prev := std
+	t.Cleanup(func() { std = prev })
+
+	opts := NewOptions()
+	opts.OutputPaths = []string{filepath.Join(t.TempDir(), "app.log")}
+	require.NoError(t, Init(opts,
+		WithContextExtractor(ContextExtractors{"tenant": func(ctx context.Context) string {
+			tenant, _ := ctx.Value(tenantKey{}).(string)
+			return tenant
+		}}),
+		WithFieldExtractors(func(ctx context.Context) (Field, bool) { return zap.Int("shard", 3), true }),
+	))
+
+	fields := std.contextFields(context.WithValue(context.Background(), tenantKey{}, "acme"))
+	assert.ElementsMatch(t, []Field{zap.String("tenant", "acme"), zap.Int("shard", 3)}, fields)
+}
+
+func TestWithFields(t *testing.T) {
+	logger, lines := newFileLogger(t, NewOptions())
+
+	ctx := WithFields(context.Background(), "request_id", "r-1")
+	child := WithFields(ctx, "user_id", 42)
+	sibling := WithFields(ctx, "route", "GET /v1/users")
+
+	logger.W(child).Infow("child")
+	logger.W(sibling).Infow("sibling")
+
+	got := lines()
+	require.Len(t, got, 2)
+	assert.Contains(t, got[0], `"request_id":"r-1"`)
+	assert.Contains(t, got[0], `"user_id":42`)
+	assert.NotContains(t, got[0], "route")
+	assert.Contains(t, got[1], `"route":"GET /v1/users"`)
+	assert.NotContains(t, got[1], "user_id")
+}
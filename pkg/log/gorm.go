@@ -2,6 +2,7 @@ package log
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 	"runtime"
@@ -9,98 +10,208 @@ import (
 	"strings"
 	"time"
 
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
 	gormlogger "gorm.io/gorm/logger"
 )
 
+// GormOptions contains the settings of the GORM logger.
+type GormOptions struct {
+	// Level is the GORM log level. Valid values are: silent, error, warn, info and debug, which is the same as info.
+	Level string `json:"level,omitempty" mapstructure:"level"`
+	// SlowThreshold is the duration above which a query is logged as slow. Zero disables slow query logging.
+	SlowThreshold time.Duration `json:"slow-threshold,omitempty" mapstructure:"slow-threshold"`
+	// IgnoreRecordNotFoundError specifies whether ErrRecordNotFound is not logged as an error.
+	IgnoreRecordNotFoundError bool `json:"ignore-record-not-found-error,omitempty" mapstructure:"ignore-record-not-found-error"`
+	// RedactParameters specifies whether the parameters of queries are replaced by placeholders in the logs.
+	RedactParameters bool `json:"redact-parameters,omitempty" mapstructure:"redact-parameters"`
+}
+
+// NewGormOptions creates a GormOptions object with default values.
+func NewGormOptions() GormOptions {
+	return GormOptions{
+		Level:                     "warn",
+		SlowThreshold:             200 * time.Millisecond,
+		IgnoreRecordNotFoundError: true,
+	}
+}
+
+var gormLevels = map[string]gormlogger.LogLevel{
+	"silent": gormlogger.Silent,
+	"error":  gormlogger.Error,
+	"warn":   gormlogger.Warn,
+	"info":   gormlogger.Info,
+	"debug":  gormlogger.Info,
+}
+
+// Validate verifies the GORM logger settings.
+func (o *GormOptions) Validate() []error {
+	errs := []error{}
+
+	if _, ok := gormLevels[o.Level]; !ok {
+		errs = append(errs, fmt.Errorf("invalid gorm log level %q: must be one of silent, error, warn, info, debug", o.Level))
+	}
+	if o.SlowThreshold < 0 {
+		errs = append(errs, errors.New("gorm slow threshold must not be negative"))
+	}
+
+	return errs
+}
+
+// AddFlags adds command line flags for the GORM logger settings, prefix is
+// the name of the database options, e.g. "mysql.".
+func (o *GormOptions) AddFlags(fs *pflag.FlagSet, prefix string) {
+	fs.StringVar(&o.Level, prefix+"log.level", o.Level, "GORM log level, one of silent, error, warn, info, debug.")
+	fs.DurationVar(&o.SlowThreshold, prefix+"log.slow-threshold", o.SlowThreshold, ""+
+		"Duration above which a query is logged as slow, 0 disables slow query logging.")
+	fs.BoolVar(&o.IgnoreRecordNotFoundError, prefix+"log.ignore-record-not-found-error", o.IgnoreRecordNotFoundError, ""+
+		"Do not log record not found errors.")
+	fs.BoolVar(&o.RedactParameters, prefix+"log.redact-parameters", o.RedactParameters, ""+
+		"Replace the parameters of queries with placeholders in the logs.")
+}
+
+// gormLogger 是结构化的 GORM 日志记录器，SQL 语句、影响的行数、耗时和调用位置以字段的形式输出.
+type gormLogger struct {
+	l     *zapLogger
+	opts  GormOptions
+	level gormlogger.LogLevel
+}
+
 var (
-	infoStr       = "%s[info] "
-	warnStr       = "%s[warn] "
-	errStr        = "%s[error] "
-	traceStr      = "[%s][%.3fms] [rows:%v] %s"
-	traceWarnStr  = "%s %s[%.3fms] [rows:%v] %s"
-	traceErrStr   = "%s %s[%.3fms] [rows:%v] %s"
-	slowThreshold = 200 * time.Millisecond
+	_ gormlogger.Interface = (*gormLogger)(nil)
+	_ gorm.ParamsFilter    = (*gormLogger)(nil)
 )
 
-var levelM = map[string]gormlogger.LogLevel{
-	"panic": gormlogger.Silent,
-	"error": gormlogger.Error,
-	"warn":  gormlogger.Warn,
-	"info":  gormlogger.Info,
+// NewGormLogger 使用全局 Logger 创建 GORM 日志记录器.
+func NewGormLogger(opts GormOptions) gormlogger.Interface {
+	return std.gormLogger(opts)
 }
 
-func (l *zapLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
-	opts := *l.opts
-	switch {
-	case level <= gormlogger.Silent:
-		opts.Level = "panic"
-	case level <= gormlogger.Error:
-		opts.Level = "error"
-	case level <= gormlogger.Warn:
-		opts.Level = "warn"
-	case level <= gormlogger.Info:
-		opts.Level = "info"
-	default:
+func (l *zapLogger) gormLogger(opts GormOptions) *gormLogger {
+	level, ok := gormLevels[opts.Level]
+	if !ok {
+		level = gormlogger.Warn
 	}
+	lc := l.clone()
+	// 调用位置由 caller 字段给出，zap 记录的调用位置是 GORM 内部的代码
+	lc.z = lc.z.WithOptions(zap.WithCaller(false))
+	return &gormLogger{l: lc, opts: opts, level: level}
+}
+
+// defaultGormLogger 返回与 Logger 日志级别一致的 GORM 日志记录器，zapLogger 通过它实现 gormlogger.Interface.
+func (l *zapLogger) defaultGormLogger() *gormLogger {
+	opts := NewGormOptions()
+	opts.Level = l.opts.Level
+	if _, ok := gormLevels[opts.Level]; !ok {
+		// dpanic 及以上级别不输出 GORM 日志
+		opts.Level = "silent"
+	}
+	return l.gormLogger(opts)
+}
 
-	return NewLogger(&opts)
+// LogMode 返回使用指定日志级别的 GORM 日志记录器.
+func (l *zapLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	return l.defaultGormLogger().LogMode(level)
 }
 
-func (l *zapLogger) Info(ctx context.Context, msg string, keyvals ...any) {
-	l.z.Sugar().Infof(infoStr+msg, append([]any{fileWithLineNum()}, keyvals...)...)
+func (l *zapLogger) Info(ctx context.Context, msg string, data ...any) {
+	l.defaultGormLogger().Info(ctx, msg, data...)
 }
 
-func (l *zapLogger) Warn(ctx context.Context, msg string, keyvals ...any) {
-	l.z.Sugar().Warnf(warnStr+msg, append([]any{fileWithLineNum()}, keyvals...)...)
+func (l *zapLogger) Warn(ctx context.Context, msg string, data ...any) {
+	l.defaultGormLogger().Warn(ctx, msg, data...)
 }
 
-func (l *zapLogger) Error(ctx context.Context, msg string, keyvals ...any) {
-	l.z.Sugar().Errorf(errStr+msg, append([]any{fileWithLineNum()}, keyvals...)...)
+func (l *zapLogger) Error(ctx context.Context, msg string, data ...any) {
+	l.defaultGormLogger().Error(ctx, msg, data...)
 }
 
 func (l *zapLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
-	if levelM[l.opts.Level] <= gormlogger.Silent {
+	l.defaultGormLogger().Trace(ctx, begin, fc, err)
+}
+
+func (g *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	copied := *g
+	copied.level = level
+	return &copied
+}
+
+func (g *gormLogger) Info(ctx context.Context, msg string, data ...any) {
+	if g.level >= gormlogger.Info {
+		g.l.withContext(ctx).z.Sugar().Infow(fmt.Sprintf(msg, data...), "caller", gormCaller())
+	}
+}
+
+func (g *gormLogger) Warn(ctx context.Context, msg string, data ...any) {
+	if g.level >= gormlogger.Warn {
+		g.l.withContext(ctx).z.Sugar().Warnw(fmt.Sprintf(msg, data...), "caller", gormCaller())
+	}
+}
+
+func (g *gormLogger) Error(ctx context.Context, msg string, data ...any) {
+	if g.level >= gormlogger.Error {
+		g.l.withContext(ctx).z.Sugar().Errorw(fmt.Sprintf(msg, data...), "caller", gormCaller())
+	}
+}
+
+// Trace 记录执行的 SQL. 执行出错时记录 error 日志，耗时超过 SlowThreshold 时记录 warn 日志，
+// 日志级别为 info 时记录所有 SQL.
+func (g *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if g.level <= gormlogger.Silent {
 		return
 	}
 
 	elapsed := time.Since(begin)
-	switch {
-	case err != nil && levelM[l.opts.Level] >= gormlogger.Error:
-		sql, rows := fc()
-		if rows == -1 {
-			l.z.Sugar().Errorf(traceErrStr, fileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, "-", sql)
-		} else {
-			l.z.Sugar().Errorf(traceErrStr, fileWithLineNum(), err, float64(elapsed.Nanoseconds())/1e6, rows, sql)
-		}
-	case elapsed > slowThreshold && slowThreshold != 0 && levelM[l.opts.Level] >= gormlogger.Warn:
+	fields := func() []zap.Field {
 		sql, rows := fc()
-		slowLog := fmt.Sprintf("SLOW SQL >= %v", slowThreshold)
-		if rows == -1 {
-			l.z.Sugar().Warnf(traceWarnStr, fileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, "-", sql)
-		} else {
-			l.z.Sugar().Warnf(traceWarnStr, fileWithLineNum(), slowLog, float64(elapsed.Nanoseconds())/1e6, rows, sql)
+		fields := []zap.Field{
+			zap.String("sql", sql),
+			zap.Float64("elapsed_ms", float64(elapsed)/float64(time.Millisecond)),
+			zap.String("caller", gormCaller()),
 		}
-	case levelM[l.opts.Level] >= gormlogger.Info:
-		sql, rows := fc()
-		if rows == -1 {
-			l.z.Sugar().Infof(traceStr, fileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, "-", sql)
-		} else {
-			l.z.Sugar().Infof(traceStr, fileWithLineNum(), float64(elapsed.Nanoseconds())/1e6, rows, sql)
+		// rows 为 -1 表示影响的行数未知
+		if rows >= 0 {
+			fields = append(fields, zap.Int64("rows", rows))
 		}
+		return fields
 	}
-}
 
-func fileWithLineNum() string {
-	for i := 4; i < 15; i++ {
-		_, file, line, ok := runtime.Caller(i)
+	// 使用传入的 context，使 SQL 日志带有与请求相同的 trace_id 等字段
+	z := g.l.withContext(ctx).z
+	switch {
+	case err != nil && g.level >= gormlogger.Error &&
+		(!errors.Is(err, gormlogger.ErrRecordNotFound) || !g.opts.IgnoreRecordNotFoundError):
+		z.Error("SQL error", append(fields(), zap.Error(err))...)
+	case g.opts.SlowThreshold != 0 && elapsed > g.opts.SlowThreshold && g.level >= gormlogger.Warn:
+		z.Warn("slow SQL", append(fields(), zap.Duration("slow_threshold", g.opts.SlowThreshold))...)
+	case g.level >= gormlogger.Info:
+		z.Info("SQL", fields()...)
+	}
+}
 
-		// if ok && (!strings.HasPrefix(file, gormSourceDir) || strings.HasSuffix(file, "_test.go")) {
-		if ok && !strings.HasSuffix(file, "_test.go") {
-			dir, f := filepath.Split(file)
+// ParamsFilter 在开启 RedactParameters 时去掉 SQL 的参数，日志中只保留占位符.
+func (g *gormLogger) ParamsFilter(ctx context.Context, sql string, params ...any) (string, []any) {
+	if g.opts.RedactParameters {
+		return sql, nil
+	}
+	return sql, params
+}
 
-			return filepath.Join(filepath.Base(dir), f) + ":" + strconv.FormatInt(int64(line), 10)
+// gormCaller 返回业务代码中调用 GORM 的位置，格式为 "目录/文件:行号".
+// 跳过 GORM 及其驱动、插件和本包的调用栈.
+func gormCaller() string {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	for {
+		frame, more := frames.Next()
+		inLog := filepath.Dir(frame.File) == logPackageDir && !strings.HasSuffix(frame.File, "_test.go")
+		if !inLog && !strings.Contains(frame.File, "gorm.io/") && !strings.HasSuffix(frame.File, ".gen.go") {
+			dir, file := filepath.Split(frame.File)
+			return filepath.Join(filepath.Base(dir), file) + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return ""
 		}
 	}
-
-	return ""
 }
@@ -0,0 +1,62 @@
+package log
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+func TestGormLogger(t *testing.T) {
+	logger, lines := newFileLogger(t, NewOptions())
+	g := logger.gormLogger(GormOptions{Level: "info", SlowThreshold: 50 * time.Millisecond, IgnoreRecordNotFoundError: true})
+	query := func() (string, int64) { return "SELECT * FROM `user` WHERE id = 1", 1 }
+
+	g.Trace(context.Background(), time.Now(), query, nil)
+	g.Trace(context.Background(), time.Now().Add(-100*time.Millisecond), query, nil)
+	g.Trace(context.Background(), time.Now(), query, errors.New("connection reset"))
+	g.Trace(context.Background(), time.Now(), query, gormlogger.ErrRecordNotFound)
+
+	got := lines()
+	require.Len(t, got, 4)
+	assert.Contains(t, got[0], `"message":"SQL"`)
+	assert.Contains(t, got[0], `"sql":"SELECT * FROM `+"`user`"+` WHERE id = 1"`)
+	assert.Contains(t, got[0], `"rows":1`)
+	assert.Contains(t, got[0], `"elapsed_ms":`)
+	assert.Contains(t, got[0], `"caller":"log/gorm_test.go:`)
+	assert.Contains(t, got[1], `"message":"slow SQL"`)
+	assert.Contains(t, got[2], `"message":"SQL error"`)
+	assert.Contains(t, got[2], `"error":"connection reset"`)
+	// The ignored record not found error is logged as a normal query.
+	assert.Contains(t, got[3], `"message":"SQL"`)
+}
+
+func TestGormLoggerLevel(t *testing.T) {
+	logger, lines := newFileLogger(t, NewOptions())
+	query := func() (string, int64) { return "SELECT 1", -1 }
+
+	g := logger.gormLogger(GormOptions{Level: "warn"})
+	g.Trace(context.Background(), time.Now(), query, nil)
+	g.LogMode(gormlogger.Silent).Trace(context.Background(), time.Now(), query, errors.New("ignored"))
+	g.LogMode(gormlogger.Info).Trace(context.Background(), time.Now(), query, nil)
+
+	got := lines()
+	require.Len(t, got, 1)
+	assert.Contains(t, got[0], `"sql":"SELECT 1"`)
+	assert.NotContains(t, got[0], `"rows"`)
+}
+
+func TestGormRedactParameters(t *testing.T) {
+	g := std.gormLogger(GormOptions{Level: "info", RedactParameters: true})
+	sql, params := g.ParamsFilter(context.Background(), "SELECT * FROM user WHERE email = ?", "alice@example.com")
+	assert.Equal(t, "SELECT * FROM user WHERE email = ?", sql)
+	assert.Empty(t, params)
+
+	g = std.gormLogger(GormOptions{Level: "info"})
+	_, params = g.ParamsFilter(context.Background(), "SELECT * FROM user WHERE email = ?", "alice@example.com")
+	assert.Equal(t, []any{"alice@example.com"}, params)
+}
@@ -0,0 +1,154 @@
+package log
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// levelOverride 记录临时修改的日志级别，到期后恢复为修改前的级别.
+var levelOverride struct {
+	mu        sync.Mutex
+	timer     *time.Timer
+	base      zapcore.Level
+	expiresAt time.Time
+}
+
+// Level 返回全局 Logger 当前的日志级别.
+func Level() zapcore.Level {
+	mu.Lock()
+	defer mu.Unlock()
+	return std.level.Level()
+}
+
+// SetLevel 修改全局 Logger 的日志级别，并取消尚未到期的临时修改.
+func SetLevel(level zapcore.Level) {
+	levelOverride.mu.Lock()
+	defer levelOverride.mu.Unlock()
+
+	stopOverride()
+	setLevel(level)
+}
+
+// SetLevelFor 临时修改全局 Logger 的日志级别，ttl 到期后恢复为第一次临时修改前的级别.
+// ttl 小于等于 0 时等同于 SetLevel.
+func SetLevelFor(level zapcore.Level, ttl time.Duration) {
+	if ttl <= 0 {
+		SetLevel(level)
+		return
+	}
+
+	levelOverride.mu.Lock()
+	defer levelOverride.mu.Unlock()
+
+	if levelOverride.timer == nil {
+		levelOverride.base = Level()
+	} else {
+		levelOverride.timer.Stop()
+	}
+	base := levelOverride.base
+	levelOverride.expiresAt = time.Now().Add(ttl)
+	var timer *time.Timer
+	timer = time.AfterFunc(ttl, func() { expireOverride(timer, base) })
+	levelOverride.timer = timer
+	setLevel(level)
+}
+
+// expireOverride 在 timer 到期时恢复 base. 回调可能在 Stop 之前已触发并等待锁，
+// 此时修改已被 SetLevel 或新的 SetLevelFor 取代，不能再恢复旧的级别.
+func expireOverride(timer *time.Timer, base zapcore.Level) {
+	levelOverride.mu.Lock()
+	defer levelOverride.mu.Unlock()
+
+	if levelOverride.timer != timer {
+		return
+	}
+	levelOverride.timer = nil
+	setLevel(base)
+	Infow("temporary log level expired", "level", base.String())
+}
+
+// stopOverride 取消尚未到期的临时修改，调用方需持有 levelOverride.mu.
+func stopOverride() {
+	if levelOverride.timer != nil {
+		levelOverride.timer.Stop()
+		levelOverride.timer = nil
+	}
+}
+
+// setLevel 修改全局 Logger 的日志级别.
+func setLevel(level zapcore.Level) {
+	mu.Lock()
+	defer mu.Unlock()
+	std.level.SetLevel(level)
+}
+
+// levelPayload 是 /debug/loglevel 接口的请求和响应体.
+type levelPayload struct {
+	Level string `json:"level"`
+	// TTL 仅用于请求，表示临时修改的有效期，例如 "10m".
+	TTL string `json:"ttl,omitempty"`
+	// ExpiresAt 仅用于响应，表示临时修改的到期时间.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// LevelHandler 返回查看和修改日志级别的 HTTP 处理器.
+// GET 返回当前级别；PUT 修改级别，请求体为 {"level":"debug","ttl":"10m"}，
+// 也可以使用查询参数 ?level=debug&ttl=10m，不指定 ttl 时永久生效.
+func LevelHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			if err := updateLevel(r); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, PUT")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		resp := levelPayload{Level: Level().String()}
+		levelOverride.mu.Lock()
+		if levelOverride.timer != nil {
+			expiresAt := levelOverride.expiresAt
+			resp.ExpiresAt = &expiresAt
+		}
+		levelOverride.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(resp)
+	})
+}
+
+// updateLevel 按请求修改日志级别.
+func updateLevel(r *http.Request) error {
+	req := levelPayload{Level: r.URL.Query().Get("level"), TTL: r.URL.Query().Get("ttl")}
+	if req.Level == "" {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return fmt.Errorf("invalid request body: %w", err)
+		}
+	}
+
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(req.Level)); err != nil {
+		return fmt.Errorf("invalid level %q: %w", req.Level, err)
+	}
+	var ttl time.Duration
+	if req.TTL != "" {
+		var err error
+		if ttl, err = time.ParseDuration(req.TTL); err != nil {
+			return fmt.Errorf("invalid ttl %q: %w", req.TTL, err)
+		}
+	}
+
+	SetLevelFor(level, ttl)
+	Infow("log level changed", "level", level.String(), "ttl", ttl)
+	return nil
+}
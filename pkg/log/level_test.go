@@ -0,0 +1,69 @@
+package log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestSetLevelFor(t *testing.T) {
+	SetLevel(zapcore.InfoLevel)
+	defer SetLevel(zapcore.InfoLevel)
+
+	SetLevelFor(zapcore.DebugLevel, 50*time.Millisecond)
+	assert.Equal(t, zapcore.DebugLevel, Level())
+	// 重复的临时修改恢复为第一次修改前的级别
+	SetLevelFor(zapcore.WarnLevel, 50*time.Millisecond)
+	assert.Equal(t, zapcore.WarnLevel, Level())
+	assert.Eventually(t, func() bool { return Level() == zapcore.InfoLevel }, time.Second, 10*time.Millisecond)
+
+	// SetLevel 取消临时修改
+	SetLevelFor(zapcore.DebugLevel, 50*time.Millisecond)
+	SetLevel(zapcore.ErrorLevel)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, zapcore.ErrorLevel, Level())
+
+	// 在 SetLevel 之前已触发、等待锁的回调不恢复旧的级别
+	SetLevelFor(zapcore.DebugLevel, time.Hour)
+	levelOverride.mu.Lock()
+	stale := levelOverride.timer
+	levelOverride.mu.Unlock()
+	SetLevel(zapcore.WarnLevel)
+	expireOverride(stale, zapcore.ErrorLevel)
+	assert.Equal(t, zapcore.WarnLevel, Level())
+}
+
+func TestLevelHandler(t *testing.T) {
+	SetLevel(zapcore.InfoLevel)
+	defer SetLevel(zapcore.InfoLevel)
+	h := LevelHandler()
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/loglevel", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"level":"info"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/debug/loglevel", strings.NewReader(`{"level":"debug","ttl":"1m"}`)))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"expires_at"`)
+	assert.Equal(t, zapcore.DebugLevel, Level())
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/debug/loglevel?level=warn", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, zapcore.WarnLevel, Level())
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/debug/loglevel?level=verbose", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/debug/loglevel", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -2,7 +2,9 @@ package log
 
 import (
 	"context"
+	"fmt"
 	"github.com/yanking/micro-zero/pkg/contract"
+	"os"
 	"sync"
 	"time"
 
@@ -20,7 +22,10 @@ type ContextExtractors map[string]func(context.Context) string
 type zapLogger struct {
 	z                 *zap.Logger
 	opts              *Options
+	level             zap.AtomicLevel                         // 运行时可修改的日志级别
+	limiter           *rateLimiter                            // 合并同一调用位置重复的 Errorw 日志
 	contextExtractors map[string]func(context.Context) string // 定义从 context 中提取字段的映射
+	fieldExtractors   []FieldExtractor                        // 从 context 中提取类型化字段的提取器
 }
 
 // Option 是一个函数类型，用于配置 zapLogger 的选项
@@ -31,7 +36,7 @@ var _ Logger = (*zapLogger)(nil)
 
 var (
 	mu  sync.Mutex
-	std = NewLogger(NewOptions())
+	std, _ = NewLogger(NewOptions())
 )
 
 // WithContextExtractor 添加自定义的 context 提取逻辑
@@ -43,15 +48,21 @@ func WithContextExtractor(contextExtractors ContextExtractors) Option {
 	}
 }
 
-// Init 使用指定的选项初始化 Logger.
-func Init(opts *Options, options ...Option) {
+// Init 使用指定的选项初始化 Logger. 创建失败时继续使用原来的 Logger 并返回错误.
+func Init(opts *Options, options ...Option) error {
+	logger, err := NewLogger(opts, options...)
+	if err != nil {
+		return err
+	}
+
 	mu.Lock()
 	defer mu.Unlock()
-	std = NewLogger(opts)
+	std = logger
+	return nil
 }
 
-// NewLogger 根据传入的 opts 创建 Logger.
-func NewLogger(opts *Options, options ...Option) *zapLogger {
+// NewLogger 根据传入的 opts 创建 Logger. 附加输出的配置不合法时返回错误.
+func NewLogger(opts *Options, options ...Option) (*zapLogger, error) {
 	if opts == nil {
 		opts = NewOptions()
 	}
@@ -88,36 +99,62 @@ func NewLogger(opts *Options, options ...Option) *zapLogger {
 		outputPaths = []string{"stdout"}
 	}
 
-	// 创建构建 zap.Logger 需要的配置
-	cfg := &zap.Config{
-		// 是否在日志中显示调用日志所在的文件和行号，例如：`"caller":"onex/onex.go:75"`
-		DisableCaller: opts.DisableCaller,
-		// 是否禁止在 panic 及以上级别打印堆栈信息
-		DisableStacktrace: opts.DisableStacktrace,
-		// 指定日志级别
-		Level: zap.NewAtomicLevelAt(zapLevel),
-		// 指定日志显示格式，可选值：console, json
-		Encoding:      opts.Format,
-		EncoderConfig: encoderConfig,
-		// 指定日志输出位置
-		OutputPaths: outputPaths,
-		// 设置 zap 内部错误输出位置
-		ErrorOutputPaths: []string{"stderr"},
+	// 保留 AtomicLevel，以便在运行时修改日志级别
+	level := zap.NewAtomicLevelAt(zapLevel)
+
+	// 指定日志显示格式，可选值：console, json
+	var encoder zapcore.Encoder
+	if opts.Format == "json" {
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	} else {
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
 	}
 
-	// 使用 cfg 创建 *zap.Logger 对象
-	z, err := cfg.Build(zap.AddStacktrace(zapcore.PanicLevel), zap.AddCallerSkip(2))
-	if err != nil {
-		panic(err)
+	// 文件输出使用按大小切割的 writer，stdout 和 stderr 保持不变
+	core := zapcore.NewCore(encoder, openOutputs(outputPaths, opts.Rotate), level)
+	// 将 syslog、网络等附加输出与上面的输出合并
+	if len(opts.Sinks) > 0 {
+		cores := []zapcore.Core{core}
+		for _, sink := range opts.Sinks {
+			sinkCore, err := newSinkCore(sink, encoderConfig, level)
+			if err != nil {
+				return nil, err
+			}
+			cores = append(cores, sinkCore)
+		}
+		core = zapcore.NewTee(cores...)
+	}
+	// 按日志级别和内容采样，避免相同的日志刷屏
+	if opts.Sampling.Initial > 0 {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, opts.Sampling.Initial, opts.Sampling.Thereafter)
 	}
 
-	logger := &zapLogger{z: z, opts: opts, contextExtractors: make(map[string]func(context.Context) string)}
+	zapOptions := []zap.Option{
+		// 设置 zap 内部错误输出位置
+		zap.ErrorOutput(zapcore.Lock(os.Stderr)),
+		zap.AddCallerSkip(2),
+	}
+	// 是否在日志中显示调用日志所在的文件和行号，例如：`"caller":"onex/onex.go:75"`
+	if !opts.DisableCaller {
+		zapOptions = append(zapOptions, zap.AddCaller())
+	}
+	// 是否禁止在 panic 及以上级别打印堆栈信息
+	if !opts.DisableStacktrace {
+		zapOptions = append(zapOptions, zap.AddStacktrace(zapcore.PanicLevel))
+	}
+	z := zap.New(core, zapOptions...)
+
+	logger := &zapLogger{z: z, opts: opts, level: level, limiter: newRateLimiter(opts.ErrorRateLimit), contextExtractors: make(map[string]func(context.Context) string)}
+	// 从 context 中的 OpenTelemetry span 提取链路信息，使日志可以和链路关联
+	if opts.EnableTraceContext {
+		WithContextExtractor(TraceExtractors())(logger)
+	}
 	// 应用所有传入的 Option
 	for _, opt := range options {
 		opt(logger)
 	}
 
-	return logger
+	return logger, nil
 }
 
 // Default 返回全局 Logger.
@@ -155,6 +192,16 @@ func (l *zapLogger) Warnf(format string, args ...any)  { l.z.Sugar().Warnf(forma
 func (l *zapLogger) Warnw(msg string, keyvals ...any)  { l.z.Sugar().Warnw(msg, keyvals...) }
 func (l *zapLogger) Errorf(format string, args ...any) { l.z.Sugar().Errorf(format, args...) }
 func (l *zapLogger) Errorw(err error, msg string, keyvals ...any) {
+	ok, suppressed := l.limiter.allow(msg, err)
+	if !ok {
+		return
+	}
+	// 限制容量，追加字段时复制而不是写入调用方的切片
+		keyvals = keyvals[:len(keyvals):len(keyvals)]
+	if suppressed > 0 {
+		msg = fmt.Sprintf("%s (suppressed %d similar messages)", msg, suppressed)
+		keyvals = append(keyvals, "suppressed", suppressed)
+	}
 	l.z.Sugar().Errorw(msg, append(keyvals, "err", err)...)
 }
 func (l *zapLogger) Panicf(format string, args ...any) { l.z.Sugar().Panicf(format, args...) }
@@ -183,12 +230,22 @@ func W(ctx context.Context) Logger {
 
 // W 方法，根据 context 提取字段并添加到日志中
 func (l *zapLogger) W(ctx context.Context) Logger {
+	return l.withContext(ctx)
+}
+
+// withContext 返回添加了 context 中提取字段的 zapLogger 副本.
+func (l *zapLogger) withContext(ctx context.Context) *zapLogger {
 	lc := l.clone()
+	if ctx == nil {
+		return lc
+	}
 
-	for fieldName, extractor := range l.contextExtractors {
-		if val := extractor(ctx); val != "" {
-			lc.z = lc.z.With(zap.String(fieldName, val))
-		}
+	if fields := l.contextFields(ctx); len(fields) > 0 {
+		lc.z = lc.z.With(fields...)
+	}
+	// WithFields 保存的字段
+	if keyvals := FieldsFromContext(ctx); len(keyvals) > 0 {
+		lc.z = lc.z.Sugar().With(keyvals...).Desugar()
 	}
 
 	return lc
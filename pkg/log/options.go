@@ -1,6 +1,8 @@
 package log
 
 import (
+	"time"
+
 	"github.com/spf13/pflag"
 	"go.uber.org/zap/zapcore"
 )
@@ -19,14 +21,32 @@ type Options struct {
 	Format string `json:"format,omitempty" mapstructure:"format"`
 	// OutputPaths specifies the output paths for the logs.
 	OutputPaths []string `json:"output-paths,omitempty" mapstructure:"output-paths"`
+	// Sinks contains the secondary sinks, e.g. syslog, the logs are sent to in addition to OutputPaths.
+	Sinks []SinkOptions `json:"sinks,omitempty" mapstructure:"sinks"`
+	// Rotate contains the rotation settings of file outputs.
+	Rotate RotateOptions `json:"rotate" mapstructure:"rotate"`
+	// Sampling contains the sampling settings of the logger.
+	Sampling SamplingOptions `json:"sampling" mapstructure:"sampling"`
+	// ErrorRateLimit is the interval within which identical errors logged by Errorw from the same call site
+	// are collapsed into one entry. Zero, the default, disables the rate limiter.
+	ErrorRateLimit time.Duration `json:"error-rate-limit,omitempty" mapstructure:"error-rate-limit"`
+	// EnableTraceContext specifies whether to add the trace_id, span_id and trace_sampled fields of the
+	// OpenTelemetry span in the context to the logs created by W.
+	EnableTraceContext bool `json:"enable-trace-context,omitempty" mapstructure:"enable-trace-context"`
+	// EnableDebugSignal specifies whether SIGUSR1 toggles the debug level.
+	EnableDebugSignal bool `json:"enable-debug-signal,omitempty" mapstructure:"enable-debug-signal"`
+	// DebugSignalTTL specifies how long the debug level enabled by SIGUSR1 lasts.
+	DebugSignalTTL time.Duration `json:"debug-signal-ttl,omitempty" mapstructure:"debug-signal-ttl"`
 }
 
 // NewOptions creates a new Options object with default values.
 func NewOptions() *Options {
 	return &Options{
-		Level:       zapcore.InfoLevel.String(),
-		Format:      "console",
-		OutputPaths: []string{"stdout"},
+		Level:          zapcore.InfoLevel.String(),
+		Format:         "console",
+		OutputPaths:    []string{"stdout"},
+		Rotate:         NewRotateOptions(),
+		DebugSignalTTL: 10 * time.Minute,
 	}
 }
 
@@ -34,6 +54,12 @@ func NewOptions() *Options {
 func (o *Options) Validate() []error {
 	errs := []error{}
 
+	for i := range o.Sinks {
+		if err := o.Sinks[i].Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	return errs
 }
 
@@ -46,4 +72,13 @@ func (o *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.BoolVar(&o.EnableColor, "log.enable-color", o.EnableColor, "Enable output ansi colors in plain format logs.")
 	fs.StringVar(&o.Format, "log.format", o.Format, "Log output `FORMAT`, support plain or json format.")
 	fs.StringSliceVar(&o.OutputPaths, "log.output-paths", o.OutputPaths, "Output paths of log.")
+	o.Rotate.AddFlags(fs)
+	o.Sampling.AddFlags(fs)
+	fs.DurationVar(&o.ErrorRateLimit, "log.error-rate-limit", o.ErrorRateLimit, ""+
+		"Interval within which identical errors from the same call site are collapsed into one entry, 0 disables it.")
+	fs.BoolVar(&o.EnableTraceContext, "log.enable-trace-context", o.EnableTraceContext, ""+
+		"Add the trace and span IDs of the OpenTelemetry span in the context to the logs.")
+	fs.BoolVar(&o.EnableDebugSignal, "log.enable-debug-signal", o.EnableDebugSignal, "Toggle the debug log level on SIGUSR1.")
+	fs.DurationVar(&o.DebugSignalTTL, "log.debug-signal-ttl", o.DebugSignalTTL, ""+
+		"Duration after which the debug log level enabled by SIGUSR1 reverts. Zero keeps it until the next signal.")
 }
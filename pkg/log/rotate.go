@@ -0,0 +1,65 @@
+package log
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// RotateOptions contains the rotation settings of file outputs.
+type RotateOptions struct {
+	// MaxSize is the maximum size in megabytes of a log file before it gets rotated.
+	MaxSize int `json:"max-size,omitempty" mapstructure:"max-size"`
+	// MaxAge is the maximum number of days to retain rotated log files. Zero keeps them regardless of age.
+	MaxAge int `json:"max-age,omitempty" mapstructure:"max-age"`
+	// MaxBackups is the maximum number of rotated log files to retain. Zero keeps all of them.
+	MaxBackups int `json:"max-backups,omitempty" mapstructure:"max-backups"`
+	// Compress specifies whether rotated log files are compressed with gzip.
+	Compress bool `json:"compress,omitempty" mapstructure:"compress"`
+	// LocalTime specifies whether the timestamps in the names of rotated log files use local time instead of UTC.
+	LocalTime bool `json:"local-time,omitempty" mapstructure:"local-time"`
+}
+
+// NewRotateOptions creates a RotateOptions object with default values.
+func NewRotateOptions() RotateOptions {
+	return RotateOptions{
+		MaxSize:   100,
+		LocalTime: true,
+	}
+}
+
+// AddFlags adds command line flags for the rotation settings.
+func (o *RotateOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&o.MaxSize, "log.rotate.max-size", o.MaxSize, "Maximum size in megabytes of a log file before it gets rotated.")
+	fs.IntVar(&o.MaxAge, "log.rotate.max-age", o.MaxAge, "Maximum number of days to retain rotated log files, 0 means no limit.")
+	fs.IntVar(&o.MaxBackups, "log.rotate.max-backups", o.MaxBackups, "Maximum number of rotated log files to retain, 0 means no limit.")
+	fs.BoolVar(&o.Compress, "log.rotate.compress", o.Compress, "Compress rotated log files with gzip.")
+	fs.BoolVar(&o.LocalTime, "log.rotate.local-time", o.LocalTime, "Use local time instead of UTC in the names of rotated log files.")
+}
+
+// openOutputs opens the output paths. stdout and stderr are written as is,
+// every other path is a file written through a rotating writer.
+func openOutputs(paths []string, rotate RotateOptions) zapcore.WriteSyncer {
+	syncers := make([]zapcore.WriteSyncer, 0, len(paths))
+	for _, path := range paths {
+		switch path {
+		case "stdout":
+			syncers = append(syncers, zapcore.Lock(os.Stdout))
+		case "stderr":
+			syncers = append(syncers, zapcore.Lock(os.Stderr))
+		default:
+			syncers = append(syncers, zapcore.AddSync(&lumberjack.Logger{
+				Filename:   strings.TrimPrefix(path, "file://"),
+				MaxSize:    rotate.MaxSize,
+				MaxAge:     rotate.MaxAge,
+				MaxBackups: rotate.MaxBackups,
+				Compress:   rotate.Compress,
+				LocalTime:  rotate.LocalTime,
+			}))
+		}
+	}
+	return zapcore.NewMultiWriteSyncer(syncers...)
+}
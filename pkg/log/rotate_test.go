@@ -0,0 +1,34 @@
+package log
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileOutputRotates(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.log")
+
+	opts := NewOptions()
+	opts.Format = "json"
+	opts.OutputPaths = []string{file}
+	opts.Rotate.MaxSize = 1
+	logger, err := NewLogger(opts)
+	require.NoError(t, err)
+
+	// 写入超过 1MB 的日志，触发切割
+	line := strings.Repeat("x", 1024)
+	for i := 0; i < 1100; i++ {
+		logger.Infow(line)
+	}
+	logger.Sync()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, len(entries), 2)
+}
@@ -0,0 +1,120 @@
+package log
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+// SamplingOptions contains the sampling settings of the logger. Sampling is
+// applied per level and message: within every second the first Initial
+// entries are logged, and after that only every Thereafter-th entry.
+type SamplingOptions struct {
+	// Initial is the number of entries with the same level and message logged per second. Zero disables sampling.
+	Initial int `json:"initial,omitempty" mapstructure:"initial"`
+	// Thereafter is the interval at which further entries with the same level and message are logged within the second.
+	Thereafter int `json:"thereafter,omitempty" mapstructure:"thereafter"`
+}
+
+// AddFlags adds command line flags for the sampling settings.
+func (o *SamplingOptions) AddFlags(fs *pflag.FlagSet) {
+	fs.IntVar(&o.Initial, "log.sampling.initial", o.Initial, ""+
+		"Number of log entries with the same level and message logged per second before sampling starts, 0 disables sampling.")
+	fs.IntVar(&o.Thereafter, "log.sampling.thereafter", o.Thereafter, ""+
+		"After the initial entries, log every Nth entry with the same level and message within the second.")
+}
+
+// maxRateLimitedSites bounds the number of call sites tracked by the error
+// rate limiter; expired ones are dropped once it is exceeded.
+const maxRateLimitedSites = 1024
+
+// rateLimiter collapses identical errors logged from the same call site
+// within an interval into one entry and a count of the suppressed ones.
+type rateLimiter struct {
+	interval time.Duration
+
+	mu    sync.Mutex
+	sites map[string]*siteState
+}
+
+type siteState struct {
+	since      time.Time
+	suppressed int
+}
+
+func newRateLimiter(interval time.Duration) *rateLimiter {
+	if interval <= 0 {
+		return nil
+	}
+	return &rateLimiter{interval: interval, sites: make(map[string]*siteState)}
+}
+
+// allow reports whether an error logged from the calling site may be written,
+// and how many identical ones have been suppressed since the last write.
+func (r *rateLimiter) allow(msg string, err error) (bool, int) {
+	if r == nil {
+		return true, 0
+	}
+
+	key := callSite() + "\x00" + msg
+	if err != nil {
+		key += "\x00" + err.Error()
+	}
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	st, ok := r.sites[key]
+	if !ok {
+		if len(r.sites) >= maxRateLimitedSites {
+			r.evict(now)
+		}
+		r.sites[key] = &siteState{since: now}
+		return true, 0
+	}
+	if now.Sub(st.since) < r.interval {
+		st.suppressed++
+		return false, 0
+	}
+
+	suppressed := st.suppressed
+	st.since, st.suppressed = now, 0
+	return true, suppressed
+}
+
+// evict drops the call sites whose interval has passed.
+func (r *rateLimiter) evict(now time.Time) {
+	for key, st := range r.sites {
+		if now.Sub(st.since) >= r.interval {
+			delete(r.sites, key)
+		}
+	}
+}
+
+// logPackageDir is the source directory of this package.
+var logPackageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// callSite returns the file and line of the first caller outside the source
+// files of this package.
+func callSite() string {
+	var pcs [16]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != logPackageDir || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFileLogger returns a JSON logger writing to a file and a function
+// returning the lines written so far.
+func newFileLogger(t *testing.T, opts *Options) (*zapLogger, func() []string) {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "app.log")
+	opts.Format = "json"
+	opts.OutputPaths = []string{file}
+	logger, err := NewLogger(opts)
+	require.NoError(t, err)
+
+	return logger, func() []string {
+		logger.Sync()
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+}
+
+func TestErrorRateLimit(t *testing.T) {
+	opts := NewOptions()
+	opts.ErrorRateLimit = 100 * time.Millisecond
+	logger, lines := newFileLogger(t, opts)
+
+	err := errors.New("connection refused")
+	logFromSameSite := func() { logger.Errorw(err, "ping failed") }
+	for i := 0; i < 5; i++ {
+		logFromSameSite()
+	}
+	// 不同的调用位置不受影响
+	logger.Errorw(err, "ping failed")
+	assert.Len(t, lines(), 2)
+
+	time.Sleep(150 * time.Millisecond)
+	logFromSameSite()
+	got := lines()
+	require.Len(t, got, 3)
+	assert.Contains(t, got[2], "suppressed 4 similar messages")
+	assert.Contains(t, got[2], `"suppressed":4`)
+}
+
+func TestSampling(t *testing.T) {
+	opts := NewOptions()
+	opts.Sampling = SamplingOptions{Initial: 3, Thereafter: 5}
+	logger, lines := newFileLogger(t, opts)
+
+	for i := 0; i < 13; i++ {
+		logger.Infow("same message")
+	}
+	// 前 3 条全部输出，之后每 5 条输出 1 条
+	assert.Len(t, lines(), 5)
+}
+
+func TestErrorwDoesNotModifyKeyvals(t *testing.T) {
+	opts := NewOptions()
+	opts.ErrorRateLimit = 50 * time.Millisecond
+	logger, lines := newFileLogger(t, opts)
+
+	err := errors.New("connection refused")
+	keyvals := make([]any, 2, 8)
+	keyvals[0], keyvals[1] = "addr", "127.0.0.1"
+	logFromSameSite := func() { logger.Errorw(err, "ping failed", keyvals...) }
+	logFromSameSite()
+	logFromSameSite()
+	time.Sleep(100 * time.Millisecond)
+	logFromSameSite()
+
+	// 调用方切片长度之外的部分没有被写入
+	assert.Equal(t, []any{nil, nil, nil, nil}, keyvals[2:6])
+	assert.Len(t, lines(), 2)
+}
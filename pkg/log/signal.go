@@ -0,0 +1,56 @@
+//go:build !windows
+
+package log
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// ToggleDebugOnSignal 在收到 SIGUSR1 时切换 debug 级别：当前不是 debug 时临时切换为 debug，
+// ttl 到期后自动恢复；当前是 debug 时立即恢复. 返回的函数用于停止监听.
+func ToggleDebugOnSignal(ttl time.Duration) (stop func()) {
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGUSR1)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ch:
+				toggleDebug(ttl)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(ch)
+		close(done)
+	}
+}
+
+// toggleDebug 切换 debug 级别.
+func toggleDebug(ttl time.Duration) {
+	levelOverride.mu.Lock()
+	overridden, base := levelOverride.timer != nil, levelOverride.base
+	levelOverride.mu.Unlock()
+
+	if Level() == zapcore.DebugLevel {
+		if !overridden {
+			// debug 是配置的级别时恢复为 info
+			base = zapcore.InfoLevel
+		}
+		SetLevel(base)
+		Infow("debug logging disabled by signal", "level", base.String())
+		return
+	}
+
+	SetLevelFor(zapcore.DebugLevel, ttl)
+	Infow("debug logging enabled by signal", "ttl", ttl)
+}
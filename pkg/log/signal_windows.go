@@ -0,0 +1,9 @@
+package log
+
+import "time"
+
+// ToggleDebugOnSignal 在 Windows 上不可用，因为没有 SIGUSR1.
+func ToggleDebugOnSignal(ttl time.Duration) (stop func()) {
+	Warnw("toggling debug logging by signal is not supported on windows")
+	return func() {}
+}
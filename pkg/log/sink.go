@@ -0,0 +1,475 @@
+package log
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+// Supported sink types.
+const (
+	// SinkTypeSyslog sends RFC 5424 syslog messages carrying the JSON encoded entry.
+	SinkTypeSyslog = "syslog"
+	// SinkTypeNetwork sends JSON encoded entries, one per line or datagram.
+	SinkTypeNetwork = "network"
+	// SinkTypeJournald sends entries to the systemd journal with its native protocol,
+	// the fields of the entry become journal fields.
+	SinkTypeJournald = "journald"
+)
+
+// defaultJournaldSocket is the socket journald receives native protocol datagrams on.
+const defaultJournaldSocket = "/run/systemd/journal/socket"
+
+const (
+	defaultSinkBufferSize = 1024
+	sinkDialTimeout       = 3 * time.Second
+	sinkWriteTimeout      = 3 * time.Second
+	sinkSyncTimeout       = time.Second
+	minReconnectDelay     = 100 * time.Millisecond
+	maxReconnectDelay     = 10 * time.Second
+)
+
+// SinkOptions contains the settings of a secondary log sink. Every sink is
+// an additional core tee'd with the outputs configured by OutputPaths.
+type SinkOptions struct {
+	// Type is the type of the sink: syslog, network or journald.
+	Type string `json:"type" mapstructure:"type"`
+	// Network is the network of the sink: tcp or udp, and additionally unix or unixgram for syslog.
+	// journald only supports unixgram, which is also its default.
+	Network string `json:"network" mapstructure:"network"`
+	// Address is the address of the sink, e.g. "127.0.0.1:514" or "/dev/log".
+	// Defaults to /run/systemd/journal/socket for journald.
+	Address string `json:"address" mapstructure:"address"`
+	// Level is the minimum level of entries sent to the sink. Empty follows the level of the logger.
+	Level string `json:"level,omitempty" mapstructure:"level"`
+	// BufferSize is the number of entries buffered while the sink is slow or unreachable.
+	// Entries logged while the buffer is full are dropped.
+	BufferSize int `json:"buffer-size,omitempty" mapstructure:"buffer-size"`
+	// Facility is the syslog facility, e.g. user or local0. Defaults to user.
+	Facility string `json:"facility,omitempty" mapstructure:"facility"`
+	// AppName is the syslog APP-NAME or the journald SYSLOG_IDENTIFIER. Defaults to the name of the executable.
+	AppName string `json:"app-name,omitempty" mapstructure:"app-name"`
+}
+
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19, "local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// Validate verifies the sink settings.
+func (o *SinkOptions) Validate() error {
+	switch o.Type {
+	case SinkTypeSyslog:
+		switch o.Network {
+		case "tcp", "udp", "unix", "unixgram":
+		default:
+			return fmt.Errorf("invalid network %q of syslog sink: must be one of tcp, udp, unix, unixgram", o.Network)
+		}
+		if _, ok := syslogFacilities[o.Facility]; o.Facility != "" && !ok {
+			return fmt.Errorf("invalid syslog facility %q", o.Facility)
+		}
+	case SinkTypeNetwork:
+		if o.Network != "tcp" && o.Network != "udp" {
+			return fmt.Errorf("invalid network %q of network sink: must be tcp or udp", o.Network)
+		}
+	case SinkTypeJournald:
+		if o.Network != "" && o.Network != "unixgram" {
+			return fmt.Errorf("invalid network %q of journald sink: must be unixgram", o.Network)
+		}
+	default:
+		return fmt.Errorf("invalid sink type %q: must be one of %s, %s, %s", o.Type, SinkTypeSyslog, SinkTypeNetwork, SinkTypeJournald)
+	}
+	if o.Address == "" && o.Type != SinkTypeJournald {
+		return fmt.Errorf("address of %s sink must not be empty", o.Type)
+	}
+	if o.Level != "" {
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(o.Level)); err != nil {
+			return fmt.Errorf("invalid level %q of %s sink %s: %w", o.Level, o.Type, o.Address, err)
+		}
+	}
+	if o.BufferSize < 0 {
+		return fmt.Errorf("buffer size of %s sink %s must not be negative", o.Type, o.Address)
+	}
+	return nil
+}
+
+// newSinkCore builds the core of a sink. Entries are encoded as JSON with
+// encoderConfig and filtered by the level of the sink, or by level if the
+// sink has none.
+func newSinkCore(o SinkOptions, encoderConfig zapcore.EncoderConfig, level zapcore.LevelEnabler) (zapcore.Core, error) {
+	if err := o.Validate(); err != nil {
+		return nil, err
+	}
+	if o.Level != "" {
+		var sinkLevel zapcore.Level
+		_ = sinkLevel.UnmarshalText([]byte(o.Level))
+		level = sinkLevel
+	}
+
+	// 颜色编码只适用于终端
+	encoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+	encoder := zapcore.NewJSONEncoder(encoderConfig)
+	octetCounting := false
+	switch o.Type {
+	case SinkTypeSyslog:
+		encoder = newSyslogEncoder(encoder, o)
+		// 流式传输使用 RFC 6587 的 octet counting 分帧
+		octetCounting = o.Network == "tcp" || o.Network == "unix"
+	case SinkTypeJournald:
+		if o.Network == "" {
+			o.Network = "unixgram"
+		}
+		if o.Address == "" {
+			o.Address = defaultJournaldSocket
+		}
+		encoder = newJournaldEncoder(o)
+	}
+
+	return zapcore.NewCore(encoder, openNetworkWriter(o, octetCounting), level), nil
+}
+
+var (
+	networkWritersMu sync.Mutex
+	// networkWriters caches the writers by destination, so that loggers
+	// created again, e.g. by Init or LogMode, share the connection.
+	networkWriters = map[string]*networkWriter{}
+)
+
+// networkWriter is a zapcore.WriteSyncer sending every write as one message
+// to a network address. Writes are queued in a bounded buffer and sent in
+// the background, reconnecting with backoff when the connection fails.
+type networkWriter struct {
+	network       string
+	address       string
+	octetCounting bool
+	queue         chan []byte
+	flush         chan chan struct{}
+	dropped       atomic.Int64
+
+	conn net.Conn
+}
+
+func openNetworkWriter(o SinkOptions, octetCounting bool) *networkWriter {
+	key := fmt.Sprintf("%s|%s://%s|%t", o.Type, o.Network, o.Address, octetCounting)
+
+	networkWritersMu.Lock()
+	defer networkWritersMu.Unlock()
+	if w, ok := networkWriters[key]; ok {
+		return w
+	}
+
+	size := o.BufferSize
+	if size == 0 {
+		size = defaultSinkBufferSize
+	}
+	w := &networkWriter{
+		network:       o.Network,
+		address:       o.Address,
+		octetCounting: octetCounting,
+		queue:         make(chan []byte, size),
+		flush:         make(chan chan struct{}),
+	}
+	networkWriters[key] = w
+	go w.run()
+	return w
+}
+
+// Write queues a copy of p, zap reuses the buffer after Write returns.
+// It never blocks: p is dropped if the buffer is full.
+func (w *networkWriter) Write(p []byte) (int, error) {
+	msg := make([]byte, len(p))
+	copy(msg, p)
+	select {
+	case w.queue <- msg:
+	default:
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Sync waits until the queued messages are sent, for at most sinkSyncTimeout.
+func (w *networkWriter) Sync() error {
+	timer := time.NewTimer(sinkSyncTimeout)
+	defer timer.Stop()
+
+	done := make(chan struct{})
+	select {
+	case w.flush <- done:
+	case <-timer.C:
+		return nil
+	}
+	select {
+	case <-done:
+	case <-timer.C:
+	}
+	return nil
+}
+
+func (w *networkWriter) run() {
+	for {
+		select {
+		case msg := <-w.queue:
+			w.send(msg)
+		case done := <-w.flush:
+			for len(w.queue) > 0 {
+				w.send(<-w.queue)
+			}
+			close(done)
+		}
+	}
+}
+
+// send writes msg, reconnecting with backoff until it succeeds. Messages
+// logged in the meantime wait in the queue.
+func (w *networkWriter) send(msg []byte) {
+	if w.octetCounting {
+		frame := strconv.AppendInt(make([]byte, 0, len(msg)+8), int64(len(msg)), 10)
+		msg = append(append(frame, ' '), msg...)
+	}
+
+	for delay := minReconnectDelay; ; delay = min(2*delay, maxReconnectDelay) {
+		if w.conn == nil {
+			conn, err := net.DialTimeout(w.network, w.address, sinkDialTimeout)
+			if err == nil {
+				w.conn = conn
+				if dropped := w.dropped.Swap(0); dropped > 0 {
+					fmt.Fprintf(os.Stderr, "log: %d entries to %s://%s were dropped because the buffer was full\n", dropped, w.network, w.address)
+				}
+			}
+		}
+		if w.conn != nil {
+			_ = w.conn.SetWriteDeadline(time.Now().Add(sinkWriteTimeout))
+			_, err := w.conn.Write(msg)
+			if err == nil {
+				return
+			}
+			if errors.Is(err, syscall.EMSGSIZE) {
+				// 重试也无法发送超过数据报上限的消息
+				fmt.Fprintf(os.Stderr, "log: entry of %d bytes to %s://%s was dropped: %v\n", len(msg), w.network, w.address, err)
+				return
+			}
+			_ = w.conn.Close()
+			w.conn = nil
+		}
+		time.Sleep(delay)
+	}
+}
+
+var syslogBufferPool = buffer.NewPool()
+
+// syslogEncoder wraps a JSON encoder and prepends the RFC 5424 header to
+// every entry: <PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD MSG.
+type syslogEncoder struct {
+	zapcore.Encoder
+	facility int
+	hostname string
+	appName  string
+	procID   string
+}
+
+func newSyslogEncoder(encoder zapcore.Encoder, o SinkOptions) *syslogEncoder {
+	facility, ok := syslogFacilities[o.Facility]
+	if !ok {
+		facility = syslogFacilities["user"]
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	appName := o.AppName
+	if appName == "" {
+		appName = filepath.Base(os.Args[0])
+	}
+	return &syslogEncoder{
+		Encoder:  encoder,
+		facility: facility,
+		hostname: hostname,
+		appName:  appName,
+		procID:   strconv.Itoa(os.Getpid()),
+	}
+}
+
+func (e *syslogEncoder) Clone() zapcore.Encoder {
+	copied := *e
+	copied.Encoder = e.Encoder.Clone()
+	return &copied
+}
+
+func (e *syslogEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	msg, err := e.Encoder.EncodeEntry(ent, fields)
+	if err != nil {
+		return nil, err
+	}
+	defer msg.Free()
+
+	buf := syslogBufferPool.Get()
+	buf.AppendByte('<')
+	buf.AppendInt(int64(e.facility*8 + syslogSeverity(ent.Level)))
+	buf.AppendString(">1 ")
+	buf.AppendString(ent.Time.Format("2006-01-02T15:04:05.000000Z07:00"))
+	buf.AppendByte(' ')
+	buf.AppendString(e.hostname)
+	buf.AppendByte(' ')
+	buf.AppendString(e.appName)
+	buf.AppendByte(' ')
+	buf.AppendString(e.procID)
+	// 没有 MSGID 和 STRUCTURED-DATA
+	buf.AppendString(" - - ")
+	data := msg.Bytes()
+	if n := len(data); n > 0 && data[n-1] == '\n' {
+		data = data[:n-1]
+	}
+	_, _ = buf.Write(data)
+	return buf, nil
+}
+
+// syslogSeverity maps a zap level to a syslog severity.
+func syslogSeverity(level zapcore.Level) int {
+	switch level {
+	case zapcore.DebugLevel:
+		return 7
+	case zapcore.InfoLevel:
+		return 6
+	case zapcore.WarnLevel:
+		return 4
+	case zapcore.ErrorLevel:
+		return 3
+	case zapcore.DPanicLevel, zapcore.PanicLevel:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// journaldEncoder encodes entries with the journald native protocol: one
+// KEY=VALUE per line, or KEY, a newline, the little endian 64 bit length and
+// the value for values containing newlines. The fields of the logger and of
+// the entry are collected by the embedded MapObjectEncoder and become journal
+// fields with upper case names.
+type journaldEncoder struct {
+	*zapcore.MapObjectEncoder
+	identifier string
+	pid        string
+}
+
+func newJournaldEncoder(o SinkOptions) *journaldEncoder {
+	identifier := o.AppName
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+	return &journaldEncoder{
+		MapObjectEncoder: zapcore.NewMapObjectEncoder(),
+		identifier:       identifier,
+		pid:              strconv.Itoa(os.Getpid()),
+	}
+}
+
+func (e *journaldEncoder) Clone() zapcore.Encoder {
+	fields := zapcore.NewMapObjectEncoder()
+	for k, v := range e.Fields {
+		fields.Fields[k] = v
+	}
+	return &journaldEncoder{MapObjectEncoder: fields, identifier: e.identifier, pid: e.pid}
+}
+
+func (e *journaldEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	all := zapcore.NewMapObjectEncoder()
+	for k, v := range e.Fields {
+		all.Fields[k] = v
+	}
+	for i := range fields {
+		fields[i].AddTo(all)
+	}
+
+	buf := syslogBufferPool.Get()
+	appendJournaldField(buf, "MESSAGE", ent.Message)
+	appendJournaldField(buf, "PRIORITY", strconv.Itoa(syslogSeverity(ent.Level)))
+	appendJournaldField(buf, "SYSLOG_IDENTIFIER", e.identifier)
+	appendJournaldField(buf, "SYSLOG_PID", e.pid)
+	if ent.LoggerName != "" {
+		appendJournaldField(buf, "LOGGER", ent.LoggerName)
+	}
+	if ent.Caller.Defined {
+		appendJournaldField(buf, "CODE_FILE", ent.Caller.File)
+		appendJournaldField(buf, "CODE_LINE", strconv.Itoa(ent.Caller.Line))
+		appendJournaldField(buf, "CODE_FUNC", ent.Caller.Function)
+	}
+	if ent.Stack != "" {
+		appendJournaldField(buf, "STACKTRACE", ent.Stack)
+	}
+
+	keys := make([]string, 0, len(all.Fields))
+	for k := range all.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		appendJournaldField(buf, journaldFieldName(k), journaldFieldValue(all.Fields[k]))
+	}
+	return buf, nil
+}
+
+func appendJournaldField(buf *buffer.Buffer, name, value string) {
+	buf.AppendString(name)
+	if !strings.Contains(value, "\n") {
+		buf.AppendByte('=')
+		buf.AppendString(value)
+		buf.AppendByte('\n')
+		return
+	}
+	buf.AppendByte('\n')
+	_, _ = buf.Write(binary.LittleEndian.AppendUint64(nil, uint64(len(value))))
+	buf.AppendString(value)
+	buf.AppendByte('\n')
+}
+
+// journaldFieldName converts key to a journal field name: upper case letters,
+// digits and underscores, not starting with an underscore or a digit, which
+// are reserved for the fields added by journald, and at most 64 characters.
+func journaldFieldName(key string) string {
+	name := []byte(strings.ToUpper(key))
+	for i, c := range name {
+		if (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			name[i] = '_'
+		}
+	}
+	if len(name) == 0 || name[0] == '_' || (name[0] >= '0' && name[0] <= '9') {
+		name = append([]byte("F_"), name...)
+	}
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return string(name)
+}
+
+func journaldFieldValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
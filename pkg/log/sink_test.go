@@ -0,0 +1,154 @@
+package log
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkSink(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	opts := NewOptions()
+	opts.OutputPaths = []string{t.TempDir() + "/app.log"}
+	opts.Sinks = []SinkOptions{{Type: SinkTypeNetwork, Network: "tcp", Address: ln.Addr().String(), Level: "warn"}}
+	logger, err := NewLogger(opts)
+	require.NoError(t, err)
+
+	logger.Infow("below the level of the sink")
+	logger.Warnw("disk almost full", "free", "1%")
+	logger.Sync()
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	assert.Contains(t, line, `"message":"disk almost full"`)
+	assert.Contains(t, line, `"level":"warn"`)
+	assert.Contains(t, line, `"free":"1%"`)
+
+	// The sink reconnects after the connection is closed by the peer.
+	require.NoError(t, conn.Close())
+	accepted := make(chan net.Conn)
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+	deadline := time.After(5 * time.Second)
+	for {
+		logger.Warnw("after reconnect")
+		logger.Sync()
+		select {
+		case conn := <-accepted:
+			defer conn.Close()
+			_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+			line, err := bufio.NewReader(conn).ReadString('\n')
+			require.NoError(t, err)
+			assert.Contains(t, line, `"message":"after reconnect`)
+			return
+		case <-deadline:
+			t.Fatal("sink did not reconnect")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func TestSyslogSink(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer pc.Close()
+
+	opts := NewOptions()
+	opts.OutputPaths = []string{t.TempDir() + "/app.log"}
+	opts.Sinks = []SinkOptions{{
+		Type:     SinkTypeSyslog,
+		Network:  "udp",
+		Address:  pc.LocalAddr().String(),
+		Facility: "local0",
+		AppName:  "apiserver",
+	}}
+	logger, err := NewLogger(opts)
+	require.NoError(t, err)
+
+	logger.Warnw("slow request", "route", "/v1/users")
+	logger.Sync()
+
+	buf := make([]byte, 4096)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	require.NoError(t, err)
+	msg := string(buf[:n])
+
+	// local0 (16) * 8 + warning (4)
+	assert.True(t, strings.HasPrefix(msg, "<132>1 "), msg)
+	assert.Contains(t, msg, " apiserver ")
+	assert.Contains(t, msg, ` - - {"level":"warn"`)
+	assert.Contains(t, msg, `"route":"/v1/users"`)
+	assert.False(t, strings.HasSuffix(msg, "\n"))
+}
+
+func TestJournaldSink(t *testing.T) {
+	socket := t.TempDir() + "/journal.sock"
+	pc, err := net.ListenPacket("unixgram", socket)
+	require.NoError(t, err)
+	defer pc.Close()
+
+	opts := NewOptions()
+	opts.OutputPaths = []string{t.TempDir() + "/app.log"}
+	opts.Sinks = []SinkOptions{{Type: SinkTypeJournald, Address: socket, AppName: "apiserver"}}
+	logger, err := NewLogger(opts)
+	require.NoError(t, err)
+
+	logger.Errorw(errors.New("connection reset"), "query failed", "request-id", "abc", "attempts", 3, "sql", "SELECT 1\nFROM dual")
+	logger.Sync()
+
+	buf := make([]byte, 4096)
+	_ = pc.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := pc.ReadFrom(buf)
+	require.NoError(t, err)
+	msg := string(buf[:n])
+
+	assert.Contains(t, msg, "MESSAGE=query failed\n")
+	assert.Contains(t, msg, "PRIORITY=3\n")
+	assert.Contains(t, msg, "SYSLOG_IDENTIFIER=apiserver\n")
+	assert.Contains(t, msg, "REQUEST_ID=abc\n")
+	assert.Contains(t, msg, "ATTEMPTS=3\n")
+	assert.Contains(t, msg, "ERR=connection reset\n")
+	// 含换行的值使用长度前缀的二进制格式
+	assert.Contains(t, msg, "SQL\n\x12\x00\x00\x00\x00\x00\x00\x00SELECT 1\nFROM dual\n")
+}
+
+func TestSinkValidate(t *testing.T) {
+	assert.NoError(t, (&SinkOptions{Type: SinkTypeSyslog, Network: "unixgram", Address: "/dev/log"}).Validate())
+	assert.NoError(t, (&SinkOptions{Type: SinkTypeJournald}).Validate())
+	assert.Error(t, (&SinkOptions{Type: SinkTypeJournald, Network: "udp", Address: "localhost:514"}).Validate())
+	assert.Error(t, (&SinkOptions{Type: "kafka", Network: "tcp", Address: "localhost:9092"}).Validate())
+	assert.Error(t, (&SinkOptions{Type: SinkTypeNetwork, Network: "unix", Address: "/tmp/log.sock"}).Validate())
+	assert.Error(t, (&SinkOptions{Type: SinkTypeSyslog, Network: "udp", Address: "localhost:514", Facility: "local9"}).Validate())
+	assert.Error(t, (&SinkOptions{Type: SinkTypeNetwork, Network: "tcp", Address: "localhost:5170", Level: "verbose"}).Validate())
+}
+
+func TestInitFailsOnInvalidSink(t *testing.T) {
+	prev := std
+	t.Cleanup(func() { std = prev })
+
+	opts := NewOptions()
+	opts.Sinks = []SinkOptions{{Type: "kafka", Network: "tcp", Address: "localhost:9092"}}
+	_, err := NewLogger(opts)
+	assert.Error(t, err)
+
+	// 创建失败时继续使用原来的 Logger
+	assert.Error(t, Init(opts))
+	assert.Same(t, prev, std)
+}
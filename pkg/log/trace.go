@@ -0,0 +1,40 @@
+package log
+
+import (
+	"context"
+	"strconv"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 内置的链路追踪字段名称.
+const (
+	TraceIDKey      = "trace_id"
+	SpanIDKey       = "span_id"
+	TraceSampledKey = "trace_sampled"
+)
+
+// TraceExtractors 返回从 context 中的 OpenTelemetry span 提取 trace_id、span_id 和采样标志的提取器.
+// context 中没有有效的 span 时，提取器返回空字符串，对应的字段不会被添加到日志中.
+func TraceExtractors() ContextExtractors {
+	return ContextExtractors{
+		TraceIDKey: func(ctx context.Context) string {
+			if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+				return sc.TraceID().String()
+			}
+			return ""
+		},
+		SpanIDKey: func(ctx context.Context) string {
+			if sc := trace.SpanContextFromContext(ctx); sc.HasSpanID() {
+				return sc.SpanID().String()
+			}
+			return ""
+		},
+		TraceSampledKey: func(ctx context.Context) string {
+			if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+				return strconv.FormatBool(sc.IsSampled())
+			}
+			return ""
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package log
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceContext(t *testing.T) {
+	opts := NewOptions()
+	opts.EnableTraceContext = true
+	logger, lines := newFileLogger(t, opts)
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	logger.W(ctx).Infow("handled request")
+	logger.W(context.Background()).Infow("no span")
+	logger.gormLogger(GormOptions{Level: "info"}).Trace(ctx, time.Now(), func() (string, int64) { return "SELECT 1", 1 }, nil)
+
+	got := lines()
+	require.Len(t, got, 3)
+	assert.Contains(t, got[0], `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+	assert.Contains(t, got[0], `"span_id":"00f067aa0ba902b7"`)
+	assert.Contains(t, got[0], `"trace_sampled":"true"`)
+	assert.NotContains(t, got[1], "trace_id")
+	assert.Contains(t, got[2], "SELECT 1")
+	assert.Contains(t, got[2], `"trace_id":"4bf92f3577b34da6a3ce929d0e0e4736"`)
+}
@@ -0,0 +1,190 @@
+package metrics
+
+import (
+	"database/sql"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"k8s.io/component-base/metrics"
+
+	"github.com/yanking/micro-zero/pkg/certwatcher"
+)
+
+var (
+	poolsMu    sync.RWMutex
+	dbStats    = map[string]func() sql.DBStats{}
+	redisStats = map[string]func() *redis.PoolStats{}
+)
+
+// RegisterDBStats registers the connection pool statistics of the named
+// database, e.g. sqlDB.Stats. The name is the db label shared with the
+// operation metrics, e.g. "mysql/onex". A later call with the same name replaces it.
+func RegisterDBStats(name string, stats func() sql.DBStats) {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	dbStats[name] = stats
+}
+
+// UnregisterDBStats removes the connection pool statistics of the named database.
+func UnregisterDBStats(name string) {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	delete(dbStats, name)
+}
+
+// RegisterRedisPoolStats registers the connection pool statistics of the
+// named Redis client, e.g. client.PoolStats. A later call with the same name
+// replaces it.
+func RegisterRedisPoolStats(name string, stats func() *redis.PoolStats) {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	redisStats[name] = stats
+}
+
+// UnregisterRedisPoolStats removes the connection pool statistics of the named Redis client.
+func UnregisterRedisPoolStats(name string) {
+	poolsMu.Lock()
+	defer poolsMu.Unlock()
+	delete(redisStats, name)
+}
+
+// sortedNames returns the keys of m in order, for a stable exposition.
+func sortedNames[V any](m map[string]V) []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+var (
+	dbMaxOpenDesc = metrics.NewDesc("db_pool_max_open_connections",
+		"Maximum number of open connections to the database.", []string{"db"}, nil, metrics.ALPHA, "")
+	dbOpenDesc = metrics.NewDesc("db_pool_open_connections",
+		"Number of established connections to the database, both in use and idle.", []string{"db"}, nil, metrics.ALPHA, "")
+	dbInUseDesc = metrics.NewDesc("db_pool_in_use_connections",
+		"Number of connections to the database currently in use.", []string{"db"}, nil, metrics.ALPHA, "")
+	dbIdleDesc = metrics.NewDesc("db_pool_idle_connections",
+		"Number of idle connections to the database.", []string{"db"}, nil, metrics.ALPHA, "")
+	dbWaitCountDesc = metrics.NewDesc("db_pool_wait_total",
+		"Number of connections waited for.", []string{"db"}, nil, metrics.ALPHA, "")
+	dbWaitDurationDesc = metrics.NewDesc("db_pool_wait_duration_seconds_total",
+		"Total time blocked waiting for a new connection.", []string{"db"}, nil, metrics.ALPHA, "")
+	dbClosedDesc = metrics.NewDesc("db_pool_closed_connections_total",
+		"Number of connections closed by the pool, by reason.", []string{"db", "reason"}, nil, metrics.ALPHA, "")
+)
+
+type dbStatsCollector struct {
+	metrics.BaseStableCollector
+}
+
+func newDBStatsCollector() metrics.StableCollector {
+	return &dbStatsCollector{}
+}
+
+func (c *dbStatsCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	ch <- dbMaxOpenDesc
+	ch <- dbOpenDesc
+	ch <- dbInUseDesc
+	ch <- dbIdleDesc
+	ch <- dbWaitCountDesc
+	ch <- dbWaitDurationDesc
+	ch <- dbClosedDesc
+}
+
+func (c *dbStatsCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	poolsMu.RLock()
+	defer poolsMu.RUnlock()
+
+	for _, name := range sortedNames(dbStats) {
+		s := dbStats[name]()
+		ch <- metrics.NewLazyConstMetric(dbMaxOpenDesc, metrics.GaugeValue, float64(s.MaxOpenConnections), name)
+		ch <- metrics.NewLazyConstMetric(dbOpenDesc, metrics.GaugeValue, float64(s.OpenConnections), name)
+		ch <- metrics.NewLazyConstMetric(dbInUseDesc, metrics.GaugeValue, float64(s.InUse), name)
+		ch <- metrics.NewLazyConstMetric(dbIdleDesc, metrics.GaugeValue, float64(s.Idle), name)
+		ch <- metrics.NewLazyConstMetric(dbWaitCountDesc, metrics.CounterValue, float64(s.WaitCount), name)
+		ch <- metrics.NewLazyConstMetric(dbWaitDurationDesc, metrics.CounterValue, s.WaitDuration.Seconds(), name)
+		ch <- metrics.NewLazyConstMetric(dbClosedDesc, metrics.CounterValue, float64(s.MaxIdleClosed), name, "max_idle")
+		ch <- metrics.NewLazyConstMetric(dbClosedDesc, metrics.CounterValue, float64(s.MaxIdleTimeClosed), name, "max_idle_time")
+		ch <- metrics.NewLazyConstMetric(dbClosedDesc, metrics.CounterValue, float64(s.MaxLifetimeClosed), name, "max_lifetime")
+	}
+}
+
+var (
+	redisHitsDesc = metrics.NewDesc("redis_pool_hits_total",
+		"Number of times a free connection was found in the pool.", []string{"client"}, nil, metrics.ALPHA, "")
+	redisMissesDesc = metrics.NewDesc("redis_pool_misses_total",
+		"Number of times a free connection was not found in the pool.", []string{"client"}, nil, metrics.ALPHA, "")
+	redisTimeoutsDesc = metrics.NewDesc("redis_pool_timeouts_total",
+		"Number of times a wait for a connection timed out.", []string{"client"}, nil, metrics.ALPHA, "")
+	redisTotalDesc = metrics.NewDesc("redis_pool_connections",
+		"Number of connections in the pool.", []string{"client"}, nil, metrics.ALPHA, "")
+	redisIdleDesc = metrics.NewDesc("redis_pool_idle_connections",
+		"Number of idle connections in the pool.", []string{"client"}, nil, metrics.ALPHA, "")
+	redisStaleDesc = metrics.NewDesc("redis_pool_stale_connections_total",
+		"Number of stale connections removed from the pool.", []string{"client"}, nil, metrics.ALPHA, "")
+)
+
+type redisPoolCollector struct {
+	metrics.BaseStableCollector
+}
+
+func newRedisPoolCollector() metrics.StableCollector {
+	return &redisPoolCollector{}
+}
+
+func (c *redisPoolCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	ch <- redisHitsDesc
+	ch <- redisMissesDesc
+	ch <- redisTimeoutsDesc
+	ch <- redisTotalDesc
+	ch <- redisIdleDesc
+	ch <- redisStaleDesc
+}
+
+func (c *redisPoolCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	poolsMu.RLock()
+	defer poolsMu.RUnlock()
+
+	for _, name := range sortedNames(redisStats) {
+		s := redisStats[name]()
+		if s == nil {
+			continue
+		}
+		ch <- metrics.NewLazyConstMetric(redisHitsDesc, metrics.CounterValue, float64(s.Hits), name)
+		ch <- metrics.NewLazyConstMetric(redisMissesDesc, metrics.CounterValue, float64(s.Misses), name)
+		ch <- metrics.NewLazyConstMetric(redisTimeoutsDesc, metrics.CounterValue, float64(s.Timeouts), name)
+		ch <- metrics.NewLazyConstMetric(redisTotalDesc, metrics.GaugeValue, float64(s.TotalConns), name)
+		ch <- metrics.NewLazyConstMetric(redisIdleDesc, metrics.GaugeValue, float64(s.IdleConns), name)
+		ch <- metrics.NewLazyConstMetric(redisStaleDesc, metrics.CounterValue, float64(s.StaleConns), name)
+	}
+}
+
+var certificateExpiryDesc = metrics.NewDesc("certificate_expiration_timestamp_seconds",
+	"Expiry of the certificates served or presented by the application, as a Unix timestamp.",
+	[]string{"file"}, nil, metrics.ALPHA, "")
+
+// certificateCollector reports the expiry of the certificates watched by certwatcher.
+type certificateCollector struct {
+	metrics.BaseStableCollector
+}
+
+func newCertificateCollector() metrics.StableCollector {
+	return &certificateCollector{}
+}
+
+func (c *certificateCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	ch <- certificateExpiryDesc
+}
+
+func (c *certificateCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	expiries := certwatcher.Expiries()
+	for _, file := range sortedNames(expiries) {
+		if notAfter := expiries[file]; notAfter != (time.Time{}) {
+			ch <- metrics.NewLazyConstMetric(certificateExpiryDesc, metrics.GaugeValue, float64(notAfter.Unix()), file)
+		}
+	}
+}
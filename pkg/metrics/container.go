@@ -0,0 +1,91 @@
+package metrics
+
+import (
+	"k8s.io/component-base/metrics"
+
+	"github.com/yanking/micro-zero/pkg/container"
+)
+
+var (
+	componentState = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Subsystem: "component",
+		Name:      "state",
+		Help:      "Lifecycle state of the components of the application, 1 for the current state.",
+	}, []string{"component", "state"})
+
+	componentStartDuration = metrics.NewHistogramVec(&metrics.HistogramOpts{
+		Subsystem: "component",
+		Name:      "start_duration_seconds",
+		Help:      "Time the components took to start and become ready.",
+		Buckets:   []float64{0.005, 0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"component", "result"})
+
+	componentStopDuration = metrics.NewHistogramVec(&metrics.HistogramOpts{
+		Subsystem: "component",
+		Name:      "stop_duration_seconds",
+		Help:      "Time the components took to stop.",
+		Buckets:   []float64{0.005, 0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30, 60},
+	}, []string{"component", "result"})
+
+	componentRestarts = metrics.NewCounterVec(&metrics.CounterOpts{
+		Subsystem: "component",
+		Name:      "restarts_total",
+		Help:      "Number of restart attempts of the components by their supervision policy.",
+	}, []string{"component"})
+)
+
+var componentStates = []container.State{
+	container.StatePending,
+	container.StateStarting,
+	container.StateReady,
+	container.StateFailed,
+	container.StateStopping,
+	container.StateStopped,
+}
+
+// ContainerEventHandler records the lifecycle events of the components. It
+// is meant to be passed to container.WithEventHandler.
+func ContainerEventHandler(e container.Event) {
+	switch e.Type {
+	case container.EventStarted:
+		setComponentState(e.Component, container.StateReady)
+		componentStartDuration.WithLabelValues(e.Component, "success").Observe(e.Duration.Seconds())
+	case container.EventStartFailed:
+		setComponentState(e.Component, container.StateFailed)
+		componentStartDuration.WithLabelValues(e.Component, "error").Observe(e.Duration.Seconds())
+	case container.EventExited:
+		// A component whose background work finished without an error is not failed.
+		if e.Err == nil {
+			setComponentState(e.Component, container.StateStopped)
+		} else {
+			setComponentState(e.Component, container.StateFailed)
+		}
+	case container.EventGaveUp:
+		setComponentState(e.Component, container.StateFailed)
+	case container.EventRestarting:
+		setComponentState(e.Component, container.StateStarting)
+		componentRestarts.WithLabelValues(e.Component).Inc()
+	case container.EventStopped:
+		setComponentState(e.Component, container.StateStopped)
+		componentStopDuration.WithLabelValues(e.Component, result(e.Err)).Observe(e.Duration.Seconds())
+	}
+}
+
+// setComponentState sets the gauge of the current state to 1 and of every
+// other state to 0.
+func setComponentState(component string, current container.State) {
+	for _, s := range componentStates {
+		value := 0.0
+		if s == current {
+			value = 1
+		}
+		componentState.WithLabelValues(component, string(s)).Set(value)
+	}
+}
+
+func result(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"time"
+
+	"k8s.io/component-base/metrics"
+)
+
+var (
+	dbOperationDuration = metrics.NewHistogramVec(&metrics.HistogramOpts{
+		Subsystem: "db_client",
+		Name:      "operation_duration_seconds",
+		Help:      "Latency of the database operations, by database, operation and table.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+	}, []string{"db", "operation", "table"})
+
+	dbOperationErrors = metrics.NewCounterVec(&metrics.CounterOpts{
+		Subsystem: "db_client",
+		Name:      "operation_errors_total",
+		Help:      "Number of failed database operations, by database, operation and error type.",
+	}, []string{"db", "operation", "error_type"})
+
+	dbRowsAffected = metrics.NewHistogramVec(&metrics.HistogramOpts{
+		Subsystem: "db_client",
+		Name:      "rows_affected",
+		Help:      "Number of rows affected or returned by the database operations, by database, operation and table.",
+		Buckets:   metrics.ExponentialBuckets(1, 4, 8),
+	}, []string{"db", "operation", "table"})
+)
+
+// UnknownTable is the table label of operations whose table is not known,
+// e.g. raw SQL.
+const UnknownTable = "unknown"
+
+// ObserveDBOperation records a database operation. errorType is empty if the
+// operation succeeded, rows is negative if the number of rows is unknown.
+func ObserveDBOperation(db, operation, table string, rows int64, errorType string, elapsed time.Duration) {
+	if table == "" {
+		table = UnknownTable
+	}
+	dbOperationDuration.WithLabelValues(db, operation, table).Observe(elapsed.Seconds())
+	if errorType != "" {
+		dbOperationErrors.WithLabelValues(db, operation, errorType).Inc()
+		return
+	}
+	if rows >= 0 {
+		dbRowsAffected.WithLabelValues(db, operation, table).Observe(float64(rows))
+	}
+}
@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"time"
+
+	"k8s.io/component-base/metrics"
+)
+
+var (
+	grpcRequests = metrics.NewCounterVec(&metrics.CounterOpts{
+		Subsystem: "grpc_server",
+		Name:      "handled_total",
+		Help:      "Number of gRPC calls handled, by method and status code.",
+	}, []string{"method", "code"})
+
+	grpcRequestDuration = metrics.NewHistogramVec(&metrics.HistogramOpts{
+		Subsystem: "grpc_server",
+		Name:      "handling_seconds",
+		Help:      "Latency of the gRPC calls, by method.",
+		Buckets:   metrics.DefBuckets,
+	}, []string{"method"})
+)
+
+// ObserveGRPCRequest records a handled gRPC call. method is the full method
+// name, e.g. "/user.v1.UserService/GetUser", and code the status code name.
+func ObserveGRPCRequest(method, code string, elapsed time.Duration) {
+	grpcRequests.WithLabelValues(method, code).Inc()
+	grpcRequestDuration.WithLabelValues(method).Observe(elapsed.Seconds())
+}
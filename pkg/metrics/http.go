@@ -0,0 +1,85 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"k8s.io/component-base/metrics"
+)
+
+// UnmatchedRoute is the route label of requests that matched no route.
+const UnmatchedRoute = "unmatched"
+
+var (
+	httpRequests = metrics.NewCounterVec(&metrics.CounterOpts{
+		Subsystem: "http_server",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests handled, by method, route and status code.",
+	}, []string{"method", "route", "code"})
+
+	httpRequestDuration = metrics.NewHistogramVec(&metrics.HistogramOpts{
+		Subsystem: "http_server",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the HTTP requests, by method and route.",
+		Buckets:   metrics.DefBuckets,
+	}, []string{"method", "route"})
+)
+
+// ObserveHTTPRequest records a handled HTTP request. route is the matched
+// route pattern, not the request path, to bound the label cardinality.
+func ObserveHTTPRequest(method, route string, code int, elapsed time.Duration) {
+	if route == "" {
+		route = UnmatchedRoute
+	}
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(code)).Inc()
+	httpRequestDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+// HTTPMiddleware records the requests handled by next. The route is the
+// pattern matched by the http.ServeMux, which sets it on the request it
+// receives, so the middleware must be placed after middlewares replacing the
+// request, e.g. by r.WithContext.
+func HTTPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		begin := time.Now()
+		sw := &StatusWriter{ResponseWriter: w}
+		next.ServeHTTP(sw, r)
+		ObserveHTTPRequest(r.Method, r.Pattern, sw.Status(), time.Since(begin))
+	})
+}
+
+// StatusWriter is an http.ResponseWriter recording the status code.
+type StatusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code and writes it to the response.
+func (w *StatusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write writes the response body, the status code is 200 if not written yet.
+func (w *StatusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Status returns the written status code, 200 if none was written.
+func (w *StatusWriter) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *StatusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,52 @@
+// Package metrics defines the Prometheus metrics of the framework and serves
+// them in the exposition format. Metrics are built on
+// k8s.io/component-base/metrics, so that --metrics.disabled-metrics,
+// --metrics.allow-metric-labels and --metrics.show-hidden-metrics-for-version
+// apply to them. The options must be applied before Register is called.
+package metrics
+
+import (
+	"net/http"
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var registerOnce sync.Once
+
+// Register registers the metrics of the framework with the global registry.
+// It is safe to call Register more than once.
+func Register() {
+	registerOnce.Do(func() {
+		legacyregistry.MustRegister(
+			componentState,
+			componentStartDuration,
+			componentStopDuration,
+			componentRestarts,
+			httpRequests,
+			httpRequestDuration,
+			grpcRequests,
+			grpcRequestDuration,
+			dbOperationDuration,
+			dbOperationErrors,
+			dbRowsAffected,
+		)
+		legacyregistry.CustomMustRegister(
+			newDBStatsCollector(),
+			newRedisPoolCollector(),
+			newCertificateCollector(),
+		)
+	})
+}
+
+// Apply applies the metrics options to the global metrics configuration.
+// It must be called before Register for the options to take effect.
+func Apply(opts *metrics.Options) {
+	opts.Apply()
+}
+
+// Handler returns the HTTP handler serving the metrics of the global registry.
+func Handler() http.Handler {
+	return legacyregistry.Handler()
+}
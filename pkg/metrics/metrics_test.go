@@ -0,0 +1,74 @@
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/component-base/metrics"
+
+	"github.com/yanking/micro-zero/pkg/container"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	opts := metrics.NewOptions()
+	opts.DisabledMetrics = []string{"grpc_server_handling_seconds"}
+	Apply(opts)
+	Register()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	handler := HTTPMiddleware(mux)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/users/1", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/v1/users/2", nil))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/unknown", nil))
+
+	ObserveGRPCRequest("/user.v1.UserService/GetUser", "OK", 10*time.Millisecond)
+
+	ContainerEventHandler(container.Event{Type: container.EventStarted, Component: "grpc-server", Duration: time.Second})
+	ContainerEventHandler(container.Event{Type: container.EventRestarting, Component: "kafka-consumer", Attempt: 1})
+	ContainerEventHandler(container.Event{Type: container.EventStopped, Component: "grpc-server", Err: errors.New("timeout")})
+	ContainerEventHandler(container.Event{Type: container.EventExited, Component: "migrator"})
+	ContainerEventHandler(container.Event{Type: container.EventExited, Component: "kafka-consumer", Err: errors.New("broker down")})
+
+	RegisterDBStats("mysql/onex", func() sql.DBStats { return sql.DBStats{MaxOpenConnections: 100, OpenConnections: 3, InUse: 1, Idle: 2} })
+	defer UnregisterDBStats("mysql/onex")
+
+	body := scrape(t)
+	assert.Contains(t, body, `http_server_requests_total{code="404",method="GET",route="GET /v1/users/{id}"} 2`)
+	assert.Contains(t, body, `http_server_requests_total{code="404",method="GET",route="unmatched"} 1`)
+	assert.Contains(t, body, `http_server_request_duration_seconds_count{method="GET",route="GET /v1/users/{id}"} 2`)
+	assert.Contains(t, body, `grpc_server_handled_total{code="OK",method="/user.v1.UserService/GetUser"} 1`)
+	assert.NotContains(t, body, "grpc_server_handling_seconds")
+	assert.Contains(t, body, `component_state{component="grpc-server",state="stopped"} 1`)
+	assert.Contains(t, body, `component_state{component="grpc-server",state="ready"} 0`)
+	assert.Contains(t, body, `component_start_duration_seconds_count{component="grpc-server",result="success"} 1`)
+	assert.Contains(t, body, `component_stop_duration_seconds_count{component="grpc-server",result="error"} 1`)
+	assert.Contains(t, body, `component_restarts_total{component="kafka-consumer"} 1`)
+	assert.Contains(t, body, `component_state{component="migrator",state="stopped"} 1`)
+	assert.Contains(t, body, `component_state{component="migrator",state="failed"} 0`)
+	assert.Contains(t, body, `component_state{component="kafka-consumer",state="failed"} 1`)
+	assert.Contains(t, body, `db_pool_open_connections{db="mysql/onex"} 3`)
+	assert.Contains(t, body, `db_pool_in_use_connections{db="mysql/onex"} 1`)
+
+	UnregisterDBStats("mysql/onex")
+	assert.NotContains(t, scrape(t), `db_pool_open_connections{db="mysql/onex"}`)
+}
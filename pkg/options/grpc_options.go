@@ -20,15 +20,19 @@ type GRPCOptions struct {
 
 	// Timeout with server timeout. Used by grpc client side.
 	Timeout time.Duration `json:"timeout" mapstructure:"timeout"`
+
+	// TLSOptions with server side TLS settings.
+	TLSOptions *ServerTLSOptions `json:"tls" mapstructure:"tls"`
 }
 
 // NewGRPCOptions is for creating an unauthenticated, unauthorized, insecure port.
 // No one should be using these anymore.
 func NewGRPCOptions() *GRPCOptions {
 	return &GRPCOptions{
-		Network: "tcp",
-		Addr:    "0.0.0.0:39090",
-		Timeout: 30 * time.Second,
+		Network:    "tcp",
+		Addr:       "0.0.0.0:39090",
+		Timeout:    30 * time.Second,
+		TLSOptions: NewServerTLSOptions(),
 	}
 }
 
@@ -40,6 +44,7 @@ func (o *GRPCOptions) Validate() []error {
 	if err := ValidateAddress(o.Addr); err != nil {
 		errors = append(errors, err)
 	}
+	errors = append(errors, o.TLSOptions.Validate()...)
 
 	return errors
 }
@@ -50,4 +55,5 @@ func (o *GRPCOptions) AddFlags(fs *pflag.FlagSet, prefixes ...string) {
 	fs.StringVar(&o.Network, "grpc.network", o.Network, "Specify the network for the gRPC server.")
 	fs.StringVar(&o.Addr, "grpc.addr", o.Addr, "Specify the gRPC server bind address and port.")
 	fs.DurationVar(&o.Timeout, "grpc.timeout", o.Timeout, "Timeout for server connections.")
+	o.TLSOptions.AddFlags(fs, "grpc")
 }
@@ -2,18 +2,19 @@
 package options
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/pprof"
+	"strings"
 
-	"github.com/gorilla/mux"
 	"github.com/spf13/pflag"
 
-	"github.com/onexstack/onexstack/pkg/log"
+	"github.com/yanking/micro-zero/pkg/log"
 )
 
 var _ IOptions = (*HealthOptions)(nil)
 
-// HealthOptions defines options for redis cluster.
+// HealthOptions defines options for the health check server.
 type HealthOptions struct {
 	// Enable debugging by exposing profiling information.
 	HTTPProfile        bool   `json:"enable-http-profiler" mapstructure:"enable-http-profiler"`
@@ -34,32 +35,64 @@ func NewHealthOptions() *HealthOptions {
 func (o *HealthOptions) Validate() []error {
 	errs := []error{}
 
+	if err := ValidateAddress(o.HealthCheckAddress); err != nil {
+		errs = append(errs, err)
+	}
+	if !strings.HasPrefix(o.HealthCheckPath, "/") {
+		errs = append(errs, fmt.Errorf("--health.check-path must start with '/'"))
+	}
+	// /livez 和 /readyz 由健康检查服务固定提供
+	if o.HealthCheckPath == "/livez" || o.HealthCheckPath == "/readyz" {
+		errs = append(errs, fmt.Errorf("--health.check-path must not be %s, it is served by the health check server", o.HealthCheckPath))
+	}
+
 	return errs
 }
 
-// AddFlags adds flags related to redis storage for a specific APIServer to the specified FlagSet.
+// AddFlags adds flags related to the health check server for a specific APIServer to the specified FlagSet.
 func (o *HealthOptions) AddFlags(fs *pflag.FlagSet, prefixes ...string) {
 	fs.BoolVar(&o.HTTPProfile, "health.enable-http-profiler", o.HTTPProfile, "Expose runtime profiling data via HTTP.")
 	fs.StringVar(&o.HealthCheckPath, "health.check-path", o.HealthCheckPath, "Specifies liveness health check request path.")
 	fs.StringVar(&o.HealthCheckAddress, "health.check-address", o.HealthCheckAddress, "Specifies liveness health check bind address.")
 }
 
-func (o *HealthOptions) ServeHealthCheck() {
-	r := mux.NewRouter()
-
-	r.HandleFunc(o.HealthCheckPath, handler).Methods(http.MethodGet)
+// NewServeMux creates the mux of the health check server. It serves the
+// profiling endpoints if they are enabled; the caller installs the health
+// check endpoints.
+func (o *HealthOptions) NewServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
 	if o.HTTPProfile {
-		r.HandleFunc("/debug/pprof/profile", pprof.Profile)
-		r.HandleFunc("/debug/pprof/{_:.*}", pprof.Index)
+		mux.HandleFunc("/debug/pprof/", pprof.Index)
+		mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+		mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+		mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+		mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	}
+	return mux
+}
+
+// ServeHealthCheck serves the health check endpoint and the profiling
+// endpoints, if they are enabled, on HealthCheckAddress. It blocks and exits
+// the process if the server fails.
+//
+// Deprecated: applications running components serve the health check
+// endpoints from the health server component, which also reports the state of
+// the components. Use the healthserver package instead.
+func (o *HealthOptions) ServeHealthCheck() {
+	mux := o.NewServeMux()
+	mux.HandleFunc(o.HealthCheckPath, handler)
 
 	log.Infow("Starting health check server", "path", o.HealthCheckPath, "addr", o.HealthCheckAddress)
-	if err := http.ListenAndServe(o.HealthCheckAddress, r); err != nil {
+	if err := http.ListenAndServe(o.HealthCheckAddress, mux); err != nil {
 		log.Fatalf("Error serving health check endpoint: %v", err)
 	}
 }
 
 func handler(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
 	rw.Header().Set("Content-type", "application/json")
 	rw.WriteHeader(http.StatusOK)
 	rw.Write([]byte(`{"status": "ok"}`))
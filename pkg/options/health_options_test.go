@@ -0,0 +1,34 @@
+package options
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHealthOptionsValidate(t *testing.T) {
+	o := NewHealthOptions()
+	assert.Empty(t, o.Validate())
+
+	// The check path must not collide with the endpoints served by the container.
+	for _, path := range []string{"/livez", "/readyz"} {
+		o.HealthCheckPath = path
+		assert.Len(t, o.Validate(), 1, path)
+	}
+
+	o.HealthCheckPath = "healthz"
+	assert.Len(t, o.Validate(), 1)
+}
+
+func TestHealthCheckHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status": "ok"}`, rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodPost, "/healthz", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
@@ -17,15 +17,20 @@ type HTTPOptions struct {
 	Addr string `json:"addr" mapstructure:"addr"`
 
 	// Timeout with server timeout. Used by http client side.
+	// Changes take effect after restart, they are not applied on config reload.
 	Timeout time.Duration `json:"timeout" mapstructure:"timeout"`
+
+	// TLSOptions with server side TLS settings.
+	TLSOptions *ServerTLSOptions `json:"tls" mapstructure:"tls"`
 }
 
 // NewHTTPOptions creates a HTTPOptions object with default parameters.
 func NewHTTPOptions() *HTTPOptions {
 	return &HTTPOptions{
-		Network: "tcp",
-		Addr:    "0.0.0.0:38443",
-		Timeout: 30 * time.Second,
+		Network:    "tcp",
+		Addr:       "0.0.0.0:38443",
+		Timeout:    30 * time.Second,
+		TLSOptions: NewServerTLSOptions(),
 	}
 }
 
@@ -41,6 +46,7 @@ func (o *HTTPOptions) Validate() []error {
 	if err := ValidateAddress(o.Addr); err != nil {
 		errors = append(errors, err)
 	}
+	errors = append(errors, o.TLSOptions.Validate()...)
 
 	return errors
 }
@@ -51,6 +57,7 @@ func (o *HTTPOptions) AddFlags(fs *pflag.FlagSet, prefixes ...string) {
 	fs.StringVar(&o.Network, "http.network", o.Network, "Specify the network for the HTTP server.")
 	fs.StringVar(&o.Addr, "http.addr", o.Addr, "Specify the HTTP server bind address and port.")
 	fs.DurationVar(&o.Timeout, "http.timeout", o.Timeout, "Timeout for server connections.")
+	o.TLSOptions.AddFlags(fs, "http")
 }
 
 // Complete fills in any fields not set that are required to have valid data.
@@ -46,6 +46,10 @@ func (o *JaegerOptions) AddFlags(fs *pflag.FlagSet, prefixes ...string) {
 	fs.StringVar(&o.Env, "jaeger.env", o.Env, "Specify the deployment environment(dev/test/staging/prod).")
 }
 
+// SetTracerProvider sets a global tracer provider exporting spans to o.Server.
+//
+// Deprecated: the provider is never flushed or shut down, so spans buffered at
+// exit are lost. Use the tracing component configured by TracingOptions instead.
 func (o *JaegerOptions) SetTracerProvider() error {
 	// Create the Jaeger exporter
 	opts := make([]otlptracegrpc.Option, 0)
@@ -1,6 +1,7 @@
 package options
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -14,6 +15,8 @@ import (
 	"k8s.io/klog/v2"
 
 	stringsutil "github.com/onexstack/onexstack/pkg/util/strings"
+
+	"github.com/yanking/micro-zero/pkg/tracing"
 )
 
 var _ IOptions = (*KafkaOptions)(nil)
@@ -169,6 +172,10 @@ type KafkaOptions struct {
 	Password      string        `mapstructure:"password"`
 	Algorithm     string        `mapstructure:"algorithm"`
 	Compressed    bool          `mapstructure:"compressed"`
+	// EnableTrace specifies whether the writer creates a producer span for every message and
+	// propagates the trace in the message headers. Consumers continue the trace with
+	// tracing.StartKafkaConsumerSpan.
+	EnableTrace bool `mapstructure:"enable-trace"`
 
 	// kafka-go writer options
 	WriterOptions WriterOptions `mapstructure:"writer"`
@@ -180,8 +187,9 @@ type KafkaOptions struct {
 // NewKafkaOptions create a `zero` value instance.
 func NewKafkaOptions() *KafkaOptions {
 	return &KafkaOptions{
-		TLSOptions: NewTLSOptions(),
-		Timeout:    3 * time.Second,
+		TLSOptions:  NewTLSOptions(),
+		Timeout:     3 * time.Second,
+		EnableTrace: true,
 		WriterOptions: WriterOptions{
 			RequiredAcks: 1,
 			MaxAttempts:  10,
@@ -251,6 +259,8 @@ func (o *KafkaOptions) AddFlags(fs *pflag.FlagSet, prefixes ...string) {
 	fs.StringVar(&o.Password, "kafka.password", o.Password, "Password of the kafka cluster.")
 	fs.StringVar(&o.Algorithm, "kafka.algorithm", o.Algorithm, "Algorithm used to create sasl.Mechanism.")
 	fs.BoolVar(&o.Compressed, "kafka.compressed", o.Compressed, "compressed is used to specify whether compress Kafka messages.")
+	fs.BoolVar(&o.EnableTrace, "kafka.enable-trace", o.EnableTrace, ""+
+		"Create a span for every message written and propagate the trace in the message headers when tracing is enabled.")
 	fs.IntVar(&o.WriterOptions.RequiredAcks, "kafka.required-acks", o.WriterOptions.RequiredAcks, ""+
 		"Number of acknowledges from partition replicas required before receiving a response to a produce request.")
 	fs.IntVar(&o.WriterOptions.MaxAttempts, "kafka.writer.max-attempts", o.WriterOptions.MaxAttempts, ""+
@@ -311,8 +321,10 @@ func (o *KafkaOptions) GetMechanism() (sasl.Mechanism, error) {
 	return mechanism, nil
 }
 
-func (o *KafkaOptions) Dialer() (*kafka.Dialer, error) {
-	tlsConfig, err := o.TLSOptions.TLSConfig()
+// Dialer returns the dialer connecting to the brokers. The client certificate and the CA
+// bundle are reloaded from disk whenever they change, until ctx is done.
+func (o *KafkaOptions) Dialer(ctx context.Context) (*kafka.Dialer, error) {
+	tlsConfig, err := o.TLSOptions.ReloadingTLSConfig(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -330,8 +342,23 @@ func (o *KafkaOptions) Dialer() (*kafka.Dialer, error) {
 	}, nil
 }
 
-func (o *KafkaOptions) Writer() (*kafka.Writer, error) {
-	dialer, err := o.Dialer()
+// KafkaWriter writes messages to kafka, it is implemented by *kafka.Writer and *tracing.KafkaWriter.
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Stats() kafka.WriterStats
+	Close() error
+}
+
+var (
+	_ KafkaWriter = (*kafka.Writer)(nil)
+	_ KafkaWriter = (*tracing.KafkaWriter)(nil)
+)
+
+// Writer returns the writer producing messages to Topic. The writer is traced if EnableTrace is set.
+// The TLS certificates of the writer are reloaded from disk until ctx is done, ctx should live as
+// long as the writer, e.g. the run context of the component owning it.
+func (o *KafkaOptions) Writer(ctx context.Context) (KafkaWriter, error) {
+	dialer, err := o.Dialer(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -359,5 +386,8 @@ func (o *KafkaOptions) Writer() (*kafka.Writer, error) {
 	}
 
 	kafkaWriter := kafka.NewWriter(config)
+	if o.EnableTrace {
+		return tracing.NewKafkaWriter(kafkaWriter), nil
+	}
 	return kafkaWriter, nil
 }
@@ -0,0 +1,29 @@
+package options
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yanking/micro-zero/pkg/tracing"
+)
+
+func TestKafkaOptionsWriter(t *testing.T) {
+	o := NewKafkaOptions()
+	o.Brokers = []string{"127.0.0.1:9092"}
+	o.Topic = "orders"
+
+	w, err := o.Writer(context.Background())
+	require.NoError(t, err)
+	defer w.Close()
+	assert.IsType(t, &tracing.KafkaWriter{}, w)
+
+	o.EnableTrace = false
+	w, err = o.Writer(context.Background())
+	require.NoError(t, err)
+	defer w.Close()
+	assert.IsType(t, &kafka.Writer{}, w)
+}
@@ -1,8 +1,12 @@
 package options
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/spf13/pflag"
 	"github.com/yanking/micro-zero/pkg/log"
+	"go.uber.org/zap/zapcore"
 )
 
 var _ IOptions = (*LogsOptions)(nil)
@@ -21,14 +25,32 @@ type LogsOptions struct {
 	Format string `json:"format,omitempty" mapstructure:"format"`
 	// OutputPaths specifies the output paths for the logs.
 	OutputPaths []string `json:"output-paths,omitempty" mapstructure:"output-paths"`
+	// Sinks contains the secondary sinks, e.g. syslog, the logs are sent to in addition to OutputPaths.
+	Sinks []log.SinkOptions `json:"sinks,omitempty" mapstructure:"sinks"`
+	// Rotate contains the rotation settings of file outputs.
+	Rotate log.RotateOptions `json:"rotate" mapstructure:"rotate"`
+	// Sampling contains the sampling settings of the logger.
+	Sampling log.SamplingOptions `json:"sampling" mapstructure:"sampling"`
+	// ErrorRateLimit is the interval within which identical errors logged by Errorw from the same call site
+	// are collapsed into one entry. Zero, the default, disables the rate limiter.
+	ErrorRateLimit time.Duration `json:"error-rate-limit,omitempty" mapstructure:"error-rate-limit"`
+	// EnableTraceContext specifies whether to add the trace_id, span_id and trace_sampled fields of the
+	// OpenTelemetry span in the context to the logs created by W.
+	EnableTraceContext bool `json:"enable-trace-context,omitempty" mapstructure:"enable-trace-context"`
+	// EnableDebugSignal specifies whether SIGUSR1 toggles the debug level.
+	EnableDebugSignal bool `json:"enable-debug-signal,omitempty" mapstructure:"enable-debug-signal"`
+	// DebugSignalTTL specifies how long the debug level enabled by SIGUSR1 lasts.
+	DebugSignalTTL time.Duration `json:"debug-signal-ttl,omitempty" mapstructure:"debug-signal-ttl"`
 }
 
 // NewLogsOptions creates an Options object with default parameters.
 func NewLogsOptions() *LogsOptions {
 	return &LogsOptions{
-		Level:       "info",
-		Format:      "console",
-		OutputPaths: []string{"stdout"},
+		Level:          "info",
+		Format:         "console",
+		OutputPaths:    []string{"stdout"},
+		Rotate:         log.NewRotateOptions(),
+		DebugSignalTTL: 10 * time.Minute,
 	}
 }
 
@@ -36,6 +58,16 @@ func NewLogsOptions() *LogsOptions {
 func (o *LogsOptions) Validate() []error {
 	errs := []error{}
 
+	var level zapcore.Level
+	if err := level.UnmarshalText([]byte(o.Level)); err != nil {
+		errs = append(errs, fmt.Errorf("invalid log level %q: %w", o.Level, err))
+	}
+	for i := range o.Sinks {
+		if err := o.Sinks[i].Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
 	return errs
 }
 
@@ -48,19 +80,37 @@ func (o *LogsOptions) AddFlags(fs *pflag.FlagSet, prefixes ...string) {
 	fs.BoolVar(&o.EnableColor, "log.enable-color", o.EnableColor, "Enable output ansi colors in plain format logs.")
 	fs.StringVar(&o.Format, "log.format", o.Format, "Log output `FORMAT`, support plain or json format.")
 	fs.StringSliceVar(&o.OutputPaths, "log.output-paths", o.OutputPaths, "Output paths of log.")
+	o.Rotate.AddFlags(fs)
+	o.Sampling.AddFlags(fs)
+	fs.DurationVar(&o.ErrorRateLimit, "log.error-rate-limit", o.ErrorRateLimit, ""+
+		"Interval within which identical errors from the same call site are collapsed into one entry, 0 disables it.")
+	fs.BoolVar(&o.EnableTraceContext, "log.enable-trace-context", o.EnableTraceContext, ""+
+		"Add the trace and span IDs of the OpenTelemetry span in the context to the logs.")
+	fs.BoolVar(&o.EnableDebugSignal, "log.enable-debug-signal", o.EnableDebugSignal, "Toggle the debug log level on SIGUSR1.")
+	fs.DurationVar(&o.DebugSignalTTL, "log.debug-signal-ttl", o.DebugSignalTTL, ""+
+		"Duration after which the debug log level enabled by SIGUSR1 reverts. Zero keeps it until the next signal.")
 }
 
 // NewLog create log  with the given config.
 func (o *LogsOptions) NewLog() (log.Logger, error) {
 	opts := &log.Options{
-		DisableCaller:     o.DisableCaller,
-		DisableStacktrace: o.DisableStacktrace,
-		EnableColor:       o.EnableColor,
-		Level:             o.Level,
-		Format:            o.Format,
-		OutputPaths:       o.OutputPaths,
+		DisableCaller:      o.DisableCaller,
+		DisableStacktrace:  o.DisableStacktrace,
+		EnableColor:        o.EnableColor,
+		Level:              o.Level,
+		Format:             o.Format,
+		OutputPaths:        o.OutputPaths,
+		Sinks:              o.Sinks,
+		Rotate:             o.Rotate,
+		Sampling:           o.Sampling,
+		ErrorRateLimit:     o.ErrorRateLimit,
+		EnableTraceContext: o.EnableTraceContext,
+		EnableDebugSignal:  o.EnableDebugSignal,
+		DebugSignalTTL:     o.DebugSignalTTL,
+	}
+	if err := log.Init(opts); err != nil {
+		return nil, err
 	}
-	log.Init(opts)
 
 	return log.Default(), nil
 }
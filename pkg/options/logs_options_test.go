@@ -0,0 +1,38 @@
+package options
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/yanking/micro-zero/pkg/log"
+)
+
+func TestLogsOptionsNewLogSinks(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	o := NewLogsOptions()
+	o.OutputPaths = []string{t.TempDir() + "/app.log"}
+	o.Sinks = []log.SinkOptions{{Type: log.SinkTypeNetwork, Network: "tcp", Address: ln.Addr().String()}}
+	require.Empty(t, o.Validate())
+
+	logger, err := o.NewLog()
+	require.NoError(t, err)
+	logger.Infow("sent to the sink", "component", "test")
+	logger.Sync()
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	assert.Contains(t, line, `"message":"sent to the sink"`)
+	assert.Contains(t, line, `"component":"test"`)
+}
@@ -1,6 +1,9 @@
 package options
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/jinzhu/copier"
 	"github.com/spf13/pflag"
 	"k8s.io/component-base/metrics"
@@ -10,6 +13,12 @@ var _ IOptions = (*MetricsOptions)(nil)
 
 // MetricsOptions has all parameters needed for exposing metrics from components.
 type MetricsOptions struct {
+	// Enabled specifies whether the metrics server is started.
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+	// Addr is the address the metrics server listens on.
+	Addr string `json:"addr" mapstructure:"addr"`
+	// Path is the path metrics are served on.
+	Path                        string            `json:"path" mapstructure:"path"`
 	ShowHiddenMetricsForVersion string            `json:"show-hidden-metrics-for-version" mapstructure:"show-hidden-metrics-for-version"`
 	DisabledMetrics             []string          `json:"disabled-metrics" mapstructure:"disabled-metrics"`
 	AllowListMapping            map[string]string `json:"allow-metric-labels" mapstructure:"allow-metric-labels"`
@@ -21,6 +30,8 @@ func NewMetricsOptions() *MetricsOptions {
 
 	var o MetricsOptions
 	_ = copier.Copy(&o, &opts)
+	o.Addr = "0.0.0.0:20251"
+	o.Path = "/metrics"
 	return &o
 }
 
@@ -32,7 +43,18 @@ func (o *MetricsOptions) Native() *metrics.Options {
 
 // Validate validates metrics flags options.
 func (o *MetricsOptions) Validate() []error {
-	return o.Native().Validate()
+	errs := o.Native().Validate()
+
+	if o.Enabled {
+		if err := ValidateAddress(o.Addr); err != nil {
+			errs = append(errs, err)
+		}
+		if !strings.HasPrefix(o.Path, "/") {
+			errs = append(errs, fmt.Errorf("--metrics.path must start with '/'"))
+		}
+	}
+
+	return errs
 }
 
 // AddFlags adds flags for exposing component metrics.
@@ -40,6 +62,9 @@ func (o *MetricsOptions) AddFlags(fs *pflag.FlagSet, prefixes ...string) {
 	if o == nil {
 		return
 	}
+	fs.BoolVar(&o.Enabled, "metrics.enabled", o.Enabled, "Start the server exposing Prometheus metrics.")
+	fs.StringVar(&o.Addr, "metrics.addr", o.Addr, "Specifies the bind address of the metrics server.")
+	fs.StringVar(&o.Path, "metrics.path", o.Path, "Specifies the path metrics are served on.")
 	fs.StringVar(&o.ShowHiddenMetricsForVersion, "metrics.show-hidden-metrics-for-version", o.ShowHiddenMetricsForVersion,
 		"The previous version for which you want to show hidden metrics. "+
 			"Only the previous minor version is meaningful, other values will not be allowed. "+
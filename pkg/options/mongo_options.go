@@ -2,14 +2,17 @@ package options
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/spf13/pflag"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
 )
 
 var _ IOptions = (*MongoOptions)(nil)
@@ -22,14 +25,25 @@ type MongoOptions struct {
 	Username   string        `json:"username" mapstructure:"username"`
 	Password   string        `json:"password" mapstructure:"password"`
 	Timeout    time.Duration `json:"timeout" mapstructure:"timeout"`
-	TLSOptions *TLSOptions   `json:"tls" mapstructure:"tls"`
+	// MaxPoolSize is the maximum number of connections to each server, 0 means no limit.
+	MaxPoolSize uint64 `json:"max-pool-size" mapstructure:"max-pool-size"`
+	// MinPoolSize is the number of idle connections kept to each server.
+	MinPoolSize uint64 `json:"min-pool-size" mapstructure:"min-pool-size"`
+	// ReadPreference is one of primary, primaryPreferred, secondary, secondaryPreferred and nearest.
+	ReadPreference string `json:"read-preference" mapstructure:"read-preference"`
+	// WriteConcern is "majority", the number of acknowledging members or a tag set name,
+	// empty means the default write concern of the server.
+	WriteConcern string      `json:"write-concern,omitempty" mapstructure:"write-concern"`
+	TLSOptions   *TLSOptions `json:"tls" mapstructure:"tls"`
 }
 
 // NewMongoOptions create a `zero` value instance.
 func NewMongoOptions() *MongoOptions {
 	return &MongoOptions{
-		Timeout:    30 * time.Second,
-		TLSOptions: NewTLSOptions(),
+		Timeout:        30 * time.Second,
+		MaxPoolSize:    100,
+		ReadPreference: readpref.PrimaryMode.String(),
+		TLSOptions:     NewTLSOptions(),
 	}
 }
 
@@ -37,6 +51,11 @@ func NewMongoOptions() *MongoOptions {
 func (o *MongoOptions) Validate() []error {
 	errs := []error{}
 
+	// MongoDB is not used if the URL is not configured
+	if o.URL == "" {
+		return errs
+	}
+
 	if _, err := url.Parse(o.URL); err != nil {
 		errs = append(errs, fmt.Errorf("unable to parse connection URL: %w", err))
 	}
@@ -49,6 +68,18 @@ func (o *MongoOptions) Validate() []error {
 		errs = append(errs, fmt.Errorf("--mongo.collection can not be empty"))
 	}
 
+	if o.MaxPoolSize > 0 && o.MinPoolSize > o.MaxPoolSize {
+		errs = append(errs, fmt.Errorf("--mongo.min-pool-size must not be greater than --mongo.max-pool-size"))
+	}
+
+	if _, err := readpref.ModeFromString(o.ReadPreference); err != nil {
+		errs = append(errs, fmt.Errorf("invalid --mongo.read-preference: %w", err))
+	}
+
+	if w, err := strconv.Atoi(o.WriteConcern); err == nil && w < 0 {
+		errs = append(errs, fmt.Errorf("--mongo.write-concern must not be negative"))
+	}
+
 	if o.TLSOptions != nil {
 		errs = append(errs, o.TLSOptions.Validate()...)
 	}
@@ -56,22 +87,57 @@ func (o *MongoOptions) Validate() []error {
 	return errs
 }
 
-// AddFlags adds flags related to redis storage for a specific APIServer to the specified FlagSet.
+// AddFlags adds flags related to mongo storage for a specific APIServer to the specified FlagSet.
 func (o *MongoOptions) AddFlags(fs *pflag.FlagSet, prefixes ...string) {
-	o.TLSOptions.AddFlags(fs, "mongo")
-
-	fs.DurationVar(&o.Timeout, "mongo.timeout", o.Timeout, "Timeout is the maximum amount of time a dial will wait for a connect to complete.")
-	fs.StringVar(&o.URL, "mongo.url", o.URL, "The MongoDB server address.")
-	fs.StringVar(&o.Database, "mongo.database", o.Database, "The MongoDB database name.")
-	fs.StringVar(&o.Collection, "mongo.collection", o.Collection, "The MongoDB collection name.")
-	fs.StringVar(&o.Username, "mongo.username", o.Username, "Username of the MongoDB database (optional).")
-	fs.StringVar(&o.Password, "mongo.password", o.Password, "Password of the MongoDB database (optional).")
+	o.TLSOptions.AddFlags(fs, append(prefixes, "mongo")...)
+
+	fs.DurationVar(&o.Timeout, join(prefixes...)+"mongo.timeout", o.Timeout, "Timeout is the maximum amount of time a dial will wait for a connect to complete.")
+	fs.StringVar(&o.URL, join(prefixes...)+"mongo.url", o.URL, "The MongoDB server address. If left blank, the following related mongo options will be ignored.")
+	fs.StringVar(&o.Database, join(prefixes...)+"mongo.database", o.Database, "The MongoDB database name.")
+	fs.StringVar(&o.Collection, join(prefixes...)+"mongo.collection", o.Collection, "The MongoDB collection name.")
+	fs.StringVar(&o.Username, join(prefixes...)+"mongo.username", o.Username, "Username of the MongoDB database (optional).")
+	fs.StringVar(&o.Password, join(prefixes...)+"mongo.password", o.Password, "Password of the MongoDB database (optional).")
+	fs.Uint64Var(&o.MaxPoolSize, join(prefixes...)+"mongo.max-pool-size", o.MaxPoolSize, ""+
+		"Maximum number of connections to each MongoDB server, 0 means no limit.")
+	fs.Uint64Var(&o.MinPoolSize, join(prefixes...)+"mongo.min-pool-size", o.MinPoolSize, ""+
+		"Number of idle connections kept to each MongoDB server.")
+	fs.StringVar(&o.ReadPreference, join(prefixes...)+"mongo.read-preference", o.ReadPreference, ""+
+		"Read preference, available options: primary, primaryPreferred, secondary, secondaryPreferred, nearest.")
+	fs.StringVar(&o.WriteConcern, join(prefixes...)+"mongo.write-concern", o.WriteConcern, ""+
+		"Write concern, majority, the number of acknowledging members or a tag set name. Empty means the server default.")
 }
 
-// NewClient creates a new MongoDB client based on the provided options.
+// NewClient creates a new MongoDB client based on the provided options. The TLS
+// certificates are loaded once, use NewClientWithContext to reload them when they change.
 func (o *MongoOptions) NewClient() (*mongo.Client, error) {
+	return o.newClient(context.Background(), func() (*tls.Config, error) {
+		return o.TLSOptions.TLSConfig()
+	})
+}
+
+// NewClientWithContext creates a new MongoDB client and pings the server, connecting is
+// bounded by ctx and the timeout. The TLS certificates are reloaded from disk whenever
+// they change, until ctx is done.
+func (o *MongoOptions) NewClientWithContext(ctx context.Context) (*mongo.Client, error) {
+	return o.newClient(ctx, func() (*tls.Config, error) {
+		return o.TLSOptions.ReloadingTLSConfig(ctx)
+	})
+}
+
+func (o *MongoOptions) newClient(ctx context.Context, tlsConfig func() (*tls.Config, error)) (*mongo.Client, error) {
+	rp, err := o.ReadPref()
+	if err != nil {
+		return nil, err
+	}
+
 	// Set client options
-	opts := options.Client().ApplyURI(o.URL).SetReadPreference(readpref.Primary())
+	opts := options.Client().ApplyURI(o.URL).
+		SetReadPreference(rp).
+		SetMaxPoolSize(o.MaxPoolSize).
+		SetMinPoolSize(o.MinPoolSize)
+	if wc := o.writeConcern(); wc != nil {
+		opts.SetWriteConcern(wc)
+	}
 	if o.Timeout > 0 {
 		opts.SetConnectTimeout(o.Timeout).SetSocketTimeout(o.Timeout).SetServerSelectionTimeout(o.Timeout)
 	}
@@ -85,15 +151,18 @@ func (o *MongoOptions) NewClient() (*mongo.Client, error) {
 	}
 
 	if o.TLSOptions != nil {
-		tlsConf, err := o.TLSOptions.TLSConfig()
+		tlsConf, err := tlsConfig()
 		if err != nil {
 			return nil, err
 		}
 		opts.SetTLSConfig(tlsConf)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), o.Timeout)
-	defer cancel()
+	if o.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Timeout)
+		defer cancel()
+	}
 
 	// Connect to MongoDB
 	client, err := mongo.Connect(ctx, opts)
@@ -102,9 +171,33 @@ func (o *MongoOptions) NewClient() (*mongo.Client, error) {
 	}
 
 	// Ping the MongoDB server to check the connection
-	if err := client.Ping(ctx, readpref.Primary()); err != nil {
+	if err := client.Ping(ctx, rp); err != nil {
+		_ = client.Disconnect(context.Background())
 		return nil, err
 	}
 
 	return client, nil
 }
+
+// ReadPref returns the read preference of ReadPreference.
+func (o *MongoOptions) ReadPref() (*readpref.ReadPref, error) {
+	mode, err := readpref.ModeFromString(o.ReadPreference)
+	if err != nil {
+		return nil, err
+	}
+	return readpref.New(mode)
+}
+
+// writeConcern returns the write concern of WriteConcern, nil means the default of the server.
+func (o *MongoOptions) writeConcern() *writeconcern.WriteConcern {
+	switch o.WriteConcern {
+	case "":
+		return nil
+	case "majority":
+		return writeconcern.Majority()
+	}
+	if w, err := strconv.Atoi(o.WriteConcern); err == nil {
+		return &writeconcern.WriteConcern{W: w}
+	}
+	return writeconcern.Custom(o.WriteConcern)
+}
@@ -8,21 +8,24 @@ import (
 
 	"github.com/spf13/pflag"
 	"gorm.io/gorm"
-	gormlogger "gorm.io/gorm/logger"
 )
 
 var _ IOptions = (*MySQLOptions)(nil)
 
 // MySQLOptions defines options for mysql database.
 type MySQLOptions struct {
-	Addr                  string        `json:"addr,omitempty" mapstructure:"addr"`
-	Username              string        `json:"username,omitempty" mapstructure:"username"`
-	Password              string        `json:"-" mapstructure:"password"`
-	Database              string        `json:"database" mapstructure:"database"`
-	MaxIdleConnections    int           `json:"max-idle-connections,omitempty" mapstructure:"max-idle-connections,omitempty"`
-	MaxOpenConnections    int           `json:"max-open-connections,omitempty" mapstructure:"max-open-connections"`
-	MaxConnectionLifeTime time.Duration `json:"max-connection-life-time,omitempty" mapstructure:"max-connection-life-time"`
-	LogLevel              int           `json:"log-level" mapstructure:"log-level"`
+	Addr                  string          `json:"addr,omitempty" mapstructure:"addr"`
+	Username              string          `json:"username,omitempty" mapstructure:"username"`
+	Password              string          `json:"-" mapstructure:"password"`
+	Database              string          `json:"database" mapstructure:"database"`
+	MaxIdleConnections    int             `json:"max-idle-connections,omitempty" mapstructure:"max-idle-connections,omitempty"`
+	MaxOpenConnections    int             `json:"max-open-connections,omitempty" mapstructure:"max-open-connections"`
+	MaxConnectionLifeTime time.Duration   `json:"max-connection-life-time,omitempty" mapstructure:"max-connection-life-time"`
+	Log                   log.GormOptions `json:"log" mapstructure:"log"`
+	// EnableMetrics specifies whether to record the latency, errors and rows affected of the statements.
+	EnableMetrics bool `json:"enable-metrics" mapstructure:"enable-metrics"`
+	// EnableTrace specifies whether to create a span for every statement when tracing is enabled.
+	EnableTrace bool `json:"enable-trace" mapstructure:"enable-trace"`
 }
 
 // NewMySQLOptions create a `zero` value instance.
@@ -35,7 +38,9 @@ func NewMySQLOptions() *MySQLOptions {
 		MaxIdleConnections:    100,
 		MaxOpenConnections:    100,
 		MaxConnectionLifeTime: time.Duration(10) * time.Second,
-		LogLevel:              1, // Silent
+		Log:                   log.NewGormOptions(),
+		EnableMetrics:         true,
+		EnableTrace:           true,
 	}
 }
 
@@ -43,6 +48,8 @@ func NewMySQLOptions() *MySQLOptions {
 func (o *MySQLOptions) Validate() []error {
 	errs := []error{}
 
+	errs = append(errs, o.Log.Validate()...)
+
 	return errs
 }
 
@@ -61,8 +68,11 @@ func (o *MySQLOptions) AddFlags(fs *pflag.FlagSet, prefixes ...string) {
 		"Maximum open connections allowed to connect to mysql.")
 	fs.DurationVar(&o.MaxConnectionLifeTime, join(prefixes...)+"mysql.max-connection-life-time", o.MaxConnectionLifeTime, ""+
 		"Maximum connection life time allowed to connect to mysql.")
-	fs.IntVar(&o.LogLevel, join(prefixes...)+"mysql.log-mode", o.LogLevel, ""+
-		"Specify gorm log level.")
+	fs.BoolVar(&o.EnableMetrics, join(prefixes...)+"mysql.enable-metrics", o.EnableMetrics, ""+
+		"Record the latency, errors and rows affected of the statements by operation and table.")
+	fs.BoolVar(&o.EnableTrace, join(prefixes...)+"mysql.enable-trace", o.EnableTrace, ""+
+		"Create a span for every statement when tracing is enabled.")
+	o.Log.AddFlags(fs, join(prefixes...)+"mysql.")
 }
 
 // DSN return DSN from MySQLOptions.
@@ -86,8 +96,28 @@ func (o *MySQLOptions) NewDB() (*gorm.DB, error) {
 		MaxIdleConnections:    o.MaxIdleConnections,
 		MaxOpenConnections:    o.MaxOpenConnections,
 		MaxConnectionLifeTime: o.MaxConnectionLifeTime,
-		Logger:                log.Default().LogMode(gormlogger.LogLevel(o.LogLevel)),
+		Logger:                log.NewGormLogger(o.Log),
+	}
+
+	gormDB, err := db.NewMySQL(opts)
+	if err != nil {
+		return nil, err
 	}
+	if o.EnableMetrics || o.EnableTrace {
+		plugin := db.NewObservabilityPlugin(db.PluginOptions{
+			Name:    o.MetricsName(),
+			Metrics: o.EnableMetrics,
+			Tracing: o.EnableTrace,
+		})
+		if err := gormDB.Use(plugin); err != nil {
+			return nil, err
+		}
+	}
+
+	return gormDB, nil
+}
 
-	return db.NewMySQL(opts)
+// MetricsName returns the db label of the metrics of the database, e.g. "mysql/onex".
+func (o *MySQLOptions) MetricsName() string {
+	return db.MetricsName("mysql", o.Database)
 }
@@ -1,18 +1,22 @@
 package options
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/spf13/pflag"
 	"gorm.io/gorm"
-	gormlogger "gorm.io/gorm/logger"
+	"k8s.io/apimachinery/pkg/util/sets"
 
-	"github.com/onexstack/onexstack/pkg/db"
-	"github.com/onexstack/onexstack/pkg/log"
+	"github.com/yanking/micro-zero/pkg/db"
+	"github.com/yanking/micro-zero/pkg/log"
 )
 
 var _ IOptions = (*PostgreSQLOptions)(nil)
 
+var availableSSLModes = sets.NewString("disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+
 // PostgreSQLOptions defines options for postgresql database.
 type PostgreSQLOptions struct {
 	Addr                  string        `json:"addr,omitempty" mapstructure:"addr"`
@@ -22,20 +26,39 @@ type PostgreSQLOptions struct {
 	MaxIdleConnections    int           `json:"max-idle-connections,omitempty" mapstructure:"max-idle-connections,omitempty"`
 	MaxOpenConnections    int           `json:"max-open-connections,omitempty" mapstructure:"max-open-connections"`
 	MaxConnectionLifeTime time.Duration `json:"max-connection-life-time,omitempty" mapstructure:"max-connection-life-time"`
-	LogLevel              int           `json:"log-level" mapstructure:"log-level"`
+	// SSLMode is one of disable, allow, prefer, require, verify-ca and verify-full.
+	SSLMode string `json:"sslmode" mapstructure:"sslmode"`
+	// SSLRootCert is the file of the CA certificates verifying the server certificate.
+	SSLRootCert string `json:"sslrootcert,omitempty" mapstructure:"sslrootcert"`
+	// TimeZone is the time zone of the session, empty means the time zone of the server.
+	TimeZone string `json:"timezone,omitempty" mapstructure:"timezone"`
+	// SearchPath is the schema search path of the session, e.g. "app,public".
+	SearchPath string `json:"search-path,omitempty" mapstructure:"search-path"`
+	// ApplicationName is reported by the server in pg_stat_activity.
+	ApplicationName string `json:"application-name,omitempty" mapstructure:"application-name"`
+	// StatementTimeout aborts statements running longer than the timeout, 0 means no timeout.
+	StatementTimeout time.Duration   `json:"statement-timeout,omitempty" mapstructure:"statement-timeout"`
+	Log              log.GormOptions `json:"log" mapstructure:"log"`
+	// EnableMetrics specifies whether to record the latency, errors and rows affected of the statements.
+	EnableMetrics bool `json:"enable-metrics" mapstructure:"enable-metrics"`
+	// EnableTrace specifies whether to create a span for every statement when tracing is enabled.
+	EnableTrace bool `json:"enable-trace" mapstructure:"enable-trace"`
 }
 
 // NewPostgreSQLOptions create a `zero` value instance.
 func NewPostgreSQLOptions() *PostgreSQLOptions {
 	return &PostgreSQLOptions{
-		Addr:                  "127.0.0.1:5432",
 		Username:              "onex",
 		Password:              "onex(#)666",
 		Database:              "onex",
 		MaxIdleConnections:    100,
 		MaxOpenConnections:    100,
 		MaxConnectionLifeTime: time.Duration(10) * time.Second,
-		LogLevel:              1, // Silent
+		SSLMode:               "disable",
+		TimeZone:              "Asia/Shanghai",
+		Log:                   log.NewGormOptions(),
+		EnableMetrics:         true,
+		EnableTrace:           true,
 	}
 }
 
@@ -43,6 +66,20 @@ func NewPostgreSQLOptions() *PostgreSQLOptions {
 func (o *PostgreSQLOptions) Validate() []error {
 	errs := []error{}
 
+	if o.Addr == "" {
+		return errs
+	}
+	if !availableSSLModes.Has(o.SSLMode) {
+		errs = append(errs, fmt.Errorf("invalid postgresql sslmode %q: must be one of %v", o.SSLMode, availableSSLModes.List()))
+	}
+	if o.SSLRootCert != "" && o.SSLMode == "disable" {
+		errs = append(errs, errors.New("--postgresql.sslrootcert requires an sslmode other than disable"))
+	}
+	if o.StatementTimeout < 0 {
+		errs = append(errs, errors.New("--postgresql.statement-timeout must not be negative"))
+	}
+	errs = append(errs, o.Log.Validate()...)
+
 	return errs
 }
 
@@ -61,8 +98,23 @@ func (o *PostgreSQLOptions) AddFlags(fs *pflag.FlagSet, prefixes ...string) {
 		"Maximum open connections allowed to connect to postgresql.")
 	fs.DurationVar(&o.MaxConnectionLifeTime, join(prefixes...)+"postgresql.max-connection-life-time", o.MaxConnectionLifeTime, ""+
 		"Maximum connection life time allowed to connect to postgresql.")
-	fs.IntVar(&o.LogLevel, join(prefixes...)+"postgresql.log-mode", o.LogLevel, ""+
-		"Specify gorm log level.")
+	fs.StringVar(&o.SSLMode, join(prefixes...)+"postgresql.sslmode", o.SSLMode, fmt.Sprintf(""+
+		"SSL mode of the connections, available options: %v.", availableSSLModes.List()))
+	fs.StringVar(&o.SSLRootCert, join(prefixes...)+"postgresql.sslrootcert", o.SSLRootCert, ""+
+		"File of the CA certificates verifying the server certificate.")
+	fs.StringVar(&o.TimeZone, join(prefixes...)+"postgresql.timezone", o.TimeZone, ""+
+		"Time zone of the sessions, empty means the time zone of the server.")
+	fs.StringVar(&o.SearchPath, join(prefixes...)+"postgresql.search-path", o.SearchPath, ""+
+		"Schema search path of the sessions, e.g. app,public.")
+	fs.StringVar(&o.ApplicationName, join(prefixes...)+"postgresql.application-name", o.ApplicationName, ""+
+		"Application name reported by the server in pg_stat_activity.")
+	fs.DurationVar(&o.StatementTimeout, join(prefixes...)+"postgresql.statement-timeout", o.StatementTimeout, ""+
+		"Abort statements running longer than the timeout, 0 means no timeout.")
+	fs.BoolVar(&o.EnableMetrics, join(prefixes...)+"postgresql.enable-metrics", o.EnableMetrics, ""+
+		"Record the latency, errors and rows affected of the statements by operation and table.")
+	fs.BoolVar(&o.EnableTrace, join(prefixes...)+"postgresql.enable-trace", o.EnableTrace, ""+
+		"Create a span for every statement when tracing is enabled.")
+	o.Log.AddFlags(fs, join(prefixes...)+"postgresql.")
 }
 
 // NewDB create postgresql store with the given config.
@@ -75,8 +127,34 @@ func (o *PostgreSQLOptions) NewDB() (*gorm.DB, error) {
 		MaxIdleConnections:    o.MaxIdleConnections,
 		MaxOpenConnections:    o.MaxOpenConnections,
 		MaxConnectionLifeTime: o.MaxConnectionLifeTime,
-		Logger:                log.Default().LogMode(gormlogger.LogLevel(o.LogLevel)),
+		SSLMode:               o.SSLMode,
+		SSLRootCert:           o.SSLRootCert,
+		TimeZone:              o.TimeZone,
+		SearchPath:            o.SearchPath,
+		ApplicationName:       o.ApplicationName,
+		StatementTimeout:      o.StatementTimeout,
+		Logger:                log.NewGormLogger(o.Log),
 	}
 
-	return db.NewPostgreSQL(opts)
+	gormDB, err := db.NewPostgreSQL(opts)
+	if err != nil {
+		return nil, err
+	}
+	if o.EnableMetrics || o.EnableTrace {
+		plugin := db.NewObservabilityPlugin(db.PluginOptions{
+			Name:    o.MetricsName(),
+			Metrics: o.EnableMetrics,
+			Tracing: o.EnableTrace,
+		})
+		if err := gormDB.Use(plugin); err != nil {
+			return nil, err
+		}
+	}
+
+	return gormDB, nil
+}
+
+// MetricsName returns the db label of the metrics of the database, e.g. "postgresql/onex".
+func (o *PostgreSQLOptions) MetricsName() string {
+	return db.MetricsName("postgresql", o.Database)
 }
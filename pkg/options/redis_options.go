@@ -3,11 +3,11 @@ package options
 import (
 	"time"
 
-	"github.com/redis/go-redis/extra/rediscensus/v9"
 	"github.com/redis/go-redis/v9"
 	"github.com/spf13/pflag"
 
 	"github.com/onexstack/onexstack/pkg/db"
+	"github.com/yanking/micro-zero/pkg/tracing"
 )
 
 var _ IOptions = (*RedisOptions)(nil)
@@ -25,7 +25,7 @@ type RedisOptions struct {
 	WriteTimeout time.Duration `json:"write-timeout" mapstructure:"write-timeout"`
 	PoolTimeout  time.Duration `json:"pool-time" mapstructure:"pool-time"`
 	PoolSize     int           `json:"pool-size" mapstructure:"pool-size"`
-	// tracing switch
+	// EnableTrace specifies whether to create a span for every command when tracing is enabled.
 	EnableTrace bool `json:"enable-trace" mapstructure:"enable-trace"`
 }
 
@@ -99,9 +99,9 @@ func (o *RedisOptions) NewClient() (*redis.Client, error) {
 		return nil, err
 	}
 
-	// hook tracing (using open telemetry)
+	// The global tracer provider is a no-op unless tracing is enabled, so the hook costs next to nothing.
 	if o.EnableTrace {
-		rdb.AddHook(rediscensus.NewTracingHook())
+		rdb.AddHook(tracing.RedisHook(o.Addr))
 	}
 
 	return rdb, nil
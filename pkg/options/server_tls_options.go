@@ -0,0 +1,184 @@
+package options
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/yanking/micro-zero/pkg/certwatcher"
+	"github.com/yanking/micro-zero/pkg/log"
+)
+
+var _ IOptions = (*ServerTLSOptions)(nil)
+
+// Client authentication modes supported by ServerTLSOptions.
+const (
+	ClientAuthNone             = "none"
+	ClientAuthRequest          = "request"
+	ClientAuthRequire          = "require"
+	ClientAuthVerifyIfGiven    = "verify-if-given"
+	ClientAuthRequireAndVerify = "require-and-verify"
+)
+
+var clientAuthTypes = map[string]tls.ClientAuthType{
+	ClientAuthNone:             tls.NoClientCert,
+	ClientAuthRequest:          tls.RequestClientCert,
+	ClientAuthRequire:          tls.RequireAnyClientCert,
+	ClientAuthVerifyIfGiven:    tls.VerifyClientCertIfGiven,
+	ClientAuthRequireAndVerify: tls.RequireAndVerifyClientCert,
+}
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ServerTLSOptions contains the TLS settings of a server. Setting a client
+// CA together with a verifying client-auth mode enables mutual TLS.
+type ServerTLSOptions struct {
+	// Enabled specifies whether the server serves TLS.
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+	// Cert is the path to the PEM encoded server certificate (chain).
+	Cert string `json:"cert" mapstructure:"cert"`
+	// Key is the path to the PEM encoded private key of Cert.
+	Key string `json:"key" mapstructure:"key"`
+	// ClientCA is the path to the PEM encoded CA bundle used to verify client
+	// certificates.
+	ClientCA string `json:"client-ca" mapstructure:"client-ca"`
+	// MinVersion is the minimum TLS version accepted, one of 1.0, 1.1, 1.2, 1.3.
+	MinVersion string `json:"min-version" mapstructure:"min-version"`
+	// CipherSuites restricts the TLS 1.0-1.2 cipher suites, by their IANA
+	// names. Empty means the Go defaults. TLS 1.3 suites are not configurable.
+	CipherSuites []string `json:"cipher-suites" mapstructure:"cipher-suites"`
+	// ClientAuth is the client certificate policy, one of none, request,
+	// require, verify-if-given, require-and-verify.
+	ClientAuth string `json:"client-auth" mapstructure:"client-auth"`
+}
+
+// NewServerTLSOptions creates a ServerTLSOptions object with TLS disabled.
+func NewServerTLSOptions() *ServerTLSOptions {
+	return &ServerTLSOptions{
+		MinVersion: "1.2",
+		ClientAuth: ClientAuthNone,
+	}
+}
+
+// Validate verifies flags passed to ServerTLSOptions.
+func (o *ServerTLSOptions) Validate() []error {
+	if o == nil || !o.Enabled {
+		return nil
+	}
+
+	errs := []error{}
+
+	if o.Cert == "" || o.Key == "" {
+		errs = append(errs, fmt.Errorf("tls: both cert and key must be set when tls is enabled"))
+	}
+	for _, file := range []string{o.Cert, o.Key, o.ClientCA} {
+		if file == "" {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			errs = append(errs, fmt.Errorf("tls: %w", err))
+		}
+	}
+
+	if _, ok := tlsVersions[o.MinVersion]; o.MinVersion != "" && !ok {
+		errs = append(errs, fmt.Errorf("tls: invalid min-version %q, must be one of %v", o.MinVersion, sets.List(sets.KeySet(tlsVersions))))
+	}
+	if _, err := cipherSuiteIDs(o.CipherSuites); err != nil {
+		errs = append(errs, err)
+	}
+
+	clientAuth, ok := clientAuthTypes[o.ClientAuth]
+	if o.ClientAuth != "" && !ok {
+		errs = append(errs, fmt.Errorf("tls: invalid client-auth %q, must be one of %v", o.ClientAuth, sets.List(sets.KeySet(clientAuthTypes))))
+	}
+	if (clientAuth == tls.VerifyClientCertIfGiven || clientAuth == tls.RequireAndVerifyClientCert) && o.ClientCA == "" {
+		errs = append(errs, fmt.Errorf("tls: client-ca must be set when client-auth is %s", o.ClientAuth))
+	}
+
+	return errs
+}
+
+// AddFlags adds flags related to server TLS to the specified FlagSet.
+func (o *ServerTLSOptions) AddFlags(fs *pflag.FlagSet, prefixes ...string) {
+	fs.BoolVar(&o.Enabled, join(prefixes...)+"tls.enabled", o.Enabled, "Serve TLS.")
+	fs.StringVar(&o.Cert, join(prefixes...)+"tls.cert", o.Cert, "Path to the PEM encoded server certificate.")
+	fs.StringVar(&o.Key, join(prefixes...)+"tls.key", o.Key, "Path to the PEM encoded private key of the server certificate.")
+	fs.StringVar(&o.ClientCA, join(prefixes...)+"tls.client-ca", o.ClientCA, "Path to the PEM encoded CA bundle used to verify client certificates.")
+	fs.StringVar(&o.MinVersion, join(prefixes...)+"tls.min-version", o.MinVersion, "Minimum TLS version accepted, one of 1.0, 1.1, 1.2, 1.3.")
+	fs.StringSliceVar(&o.CipherSuites, join(prefixes...)+"tls.cipher-suites", o.CipherSuites, ""+
+		"Comma-separated list of TLS 1.0-1.2 cipher suites by IANA name. If omitted, the Go defaults are used.")
+	fs.StringVar(&o.ClientAuth, join(prefixes...)+"tls.client-auth", o.ClientAuth, ""+
+		"Client certificate policy, one of none, request, require, verify-if-given, require-and-verify.")
+}
+
+// ServerTLSConfig builds the server side tls.Config. It returns nil if TLS is
+// disabled. The certificate and the client CA bundle are reloaded from disk
+// whenever they change, until ctx is done.
+func (o *ServerTLSOptions) ServerTLSConfig(ctx context.Context) (*tls.Config, error) {
+	if o == nil || !o.Enabled {
+		return nil, nil
+	}
+
+	watcher, err := certwatcher.New(o.Cert, o.Key, certwatcher.WithCAFile(o.ClientCA))
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := watcher.Start(ctx); err != nil {
+			log.Errorw(err, "failed to watch tls certificate, certificate rotation is disabled", "cert", o.Cert)
+		}
+	}()
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ClientAuth: tls.NoClientCert,
+	}
+	if v, ok := tlsVersions[o.MinVersion]; ok {
+		tlsConfig.MinVersion = v
+	}
+	if tlsConfig.CipherSuites, err = cipherSuiteIDs(o.CipherSuites); err != nil {
+		return nil, err
+	}
+	if clientAuth, ok := clientAuthTypes[o.ClientAuth]; ok {
+		tlsConfig.ClientAuth = clientAuth
+	}
+
+	return watcher.ServerConfig(tlsConfig), nil
+}
+
+// cipherSuiteIDs maps IANA cipher suite names to their IDs.
+func cipherSuiteIDs(names []string) ([]uint16, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	known := make(map[string]uint16)
+	for _, s := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		known[s.Name] = s.ID
+	}
+
+	ids := make([]uint16, 0, len(names))
+	var unknown []string
+	for _, name := range names {
+		id, ok := known[strings.TrimSpace(name)]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("tls: unknown cipher suites %v", unknown)
+	}
+	return ids, nil
+}
@@ -0,0 +1,141 @@
+package options
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPKI holds the files of a CA and of a server and a client certificate
+// signed by it.
+type testPKI struct {
+	caCert, serverCert, serverKey, clientCert, clientKey string
+	ca                                                   *x509.Certificate
+	caKey                                                *ecdsa.PrivateKey
+}
+
+func newTestPKI(t *testing.T) *testPKI {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	p := &testPKI{ca: ca, caKey: caKey, caCert: filepath.Join(dir, "ca.crt")}
+	writePEM(t, p.caCert, "CERTIFICATE", caDER)
+	p.serverCert, p.serverKey = p.issue(t, dir, "server", time.Hour, x509.ExtKeyUsageServerAuth)
+	p.clientCert, p.clientKey = p.issue(t, dir, "client", time.Hour, x509.ExtKeyUsageClientAuth)
+	return p
+}
+
+// issue writes a certificate signed by the CA and its key to dir.
+func (p *testPKI) issue(t *testing.T, dir, name string, validFor time.Duration, usage x509.ExtKeyUsage) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(validFor),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, p.ca, &key.PublicKey, p.caKey)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(dir, name+".crt"), filepath.Join(dir, name+".key")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+	return certFile, keyFile
+}
+
+func writePEM(t *testing.T, file, blockType string, der []byte) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+}
+
+func TestServerTLSOptionsValidate(t *testing.T) {
+	o := NewServerTLSOptions()
+	assert.Empty(t, o.Validate())
+
+	o.Enabled = true
+	o.Cert = "/does/not/exist.crt"
+	o.MinVersion = "1.4"
+	o.CipherSuites = []string{"TLS_NOT_A_SUITE"}
+	o.ClientAuth = ClientAuthRequireAndVerify
+	assert.Len(t, o.Validate(), 5)
+
+	pki := newTestPKI(t)
+	o = NewServerTLSOptions()
+	o.Enabled = true
+	o.Cert, o.Key, o.ClientCA = pki.serverCert, pki.serverKey, pki.caCert
+	o.CipherSuites = []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}
+	o.ClientAuth = ClientAuthRequireAndVerify
+	assert.Empty(t, o.Validate())
+}
+
+func TestServerTLSConfigMutualTLS(t *testing.T) {
+	pki := newTestPKI(t)
+	o := NewServerTLSOptions()
+	o.Enabled = true
+	o.Cert, o.Key, o.ClientCA = pki.serverCert, pki.serverKey, pki.caCert
+	o.ClientAuth = ClientAuthRequireAndVerify
+
+	tlsConfig, err := o.ServerTLSConfig(context.Background())
+	require.NoError(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = tlsConfig
+	srv.StartTLS()
+	defer srv.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(pki.ca)
+	clientCert, err := tls.LoadX509KeyPair(pki.clientCert, pki.clientKey)
+	require.NoError(t, err)
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: []tls.Certificate{clientCert}}}}
+	resp, err := client.Get(srv.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// 未出示客户端证书的请求被拒绝
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots}}}
+	_, err = client.Get(srv.URL)
+	assert.Error(t, err)
+}
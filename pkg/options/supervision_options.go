@@ -0,0 +1,48 @@
+package options
+
+import (
+	"fmt"
+	"time"
+)
+
+// Restart policies supported by SupervisionOptions.
+const (
+	RestartNever     = "never"
+	RestartOnFailure = "on-failure"
+	RestartAlways    = "always"
+)
+
+// SupervisionOptions contains the supervision policy of a component, it is
+// converted to the policy of the component container when the application starts.
+type SupervisionOptions struct {
+	// Restart is one of never, on-failure and always.
+	Restart string `json:"restart" mapstructure:"restart"`
+	// MaxRetries limits the consecutive restart attempts of on-failure, zero means unlimited.
+	MaxRetries int `json:"max-retries" mapstructure:"max-retries"`
+	// InitialBackoff is the delay before the first restart attempt, it doubles
+	// with every further attempt up to MaxBackoff.
+	InitialBackoff time.Duration `json:"initial-backoff" mapstructure:"initial-backoff"`
+	// MaxBackoff caps the delay between restart attempts.
+	MaxBackoff time.Duration `json:"max-backoff" mapstructure:"max-backoff"`
+	// Critical components tear the application down once they fail for good.
+	Critical bool `json:"critical" mapstructure:"critical"`
+}
+
+// Validate verifies the supervision policy.
+func (o *SupervisionOptions) Validate() []error {
+	errs := []error{}
+
+	switch o.Restart {
+	case RestartNever, RestartOnFailure, RestartAlways:
+	default:
+		errs = append(errs, fmt.Errorf("invalid restart policy %q: must be one of %s, %s, %s", o.Restart, RestartNever, RestartOnFailure, RestartAlways))
+	}
+	if o.MaxRetries < 0 {
+		errs = append(errs, fmt.Errorf("max-retries must not be negative"))
+	}
+	if o.InitialBackoff < 0 || o.MaxBackoff < 0 {
+		errs = append(errs, fmt.Errorf("initial-backoff and max-backoff must not be negative"))
+	}
+
+	return errs
+}
@@ -1,6 +1,7 @@
 package options
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
@@ -8,6 +9,9 @@ import (
 	"os"
 
 	"github.com/spf13/pflag"
+
+	"github.com/yanking/micro-zero/pkg/certwatcher"
+	"github.com/yanking/micro-zero/pkg/log"
 )
 
 var _ IOptions = (*TLSOptions)(nil)
@@ -106,6 +110,26 @@ func (o *TLSOptions) TLSConfig() (*tls.Config, error) {
 	return tlsConfig, nil
 }
 
+// ReloadingTLSConfig is like TLSConfig, but the client certificate and the CA
+// bundle are reloaded from disk whenever they change, until ctx is done.
+func (o *TLSOptions) ReloadingTLSConfig(ctx context.Context) (*tls.Config, error) {
+	if !o.UseTLS {
+		return nil, nil
+	}
+
+	watcher, err := certwatcher.New(o.Cert, o.Key, certwatcher.WithCAFile(o.CaCert))
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		if err := watcher.Start(ctx); err != nil {
+			log.Errorw(err, "failed to watch tls certificate, certificate rotation is disabled", "cert", o.Cert)
+		}
+	}()
+
+	return watcher.ClientConfig(&tls.Config{InsecureSkipVerify: o.InsecureSkipVerify}), nil
+}
+
 // Scheme returns the URL scheme based on the TLS configuration.
 func (o *TLSOptions) Scheme() string {
 	if o.UseTLS {
@@ -0,0 +1,125 @@
+package options
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+)
+
+var _ IOptions = (*TracingOptions)(nil)
+
+// Supported trace exporters.
+const (
+	TraceExporterOTLPGRPC = "otlp-grpc"
+	TraceExporterOTLPHTTP = "otlp-http"
+	TraceExporterStdout   = "stdout"
+	TraceExporterFile     = "file"
+)
+
+// Supported trace samplers, named after the values of OTEL_TRACES_SAMPLER.
+const (
+	TraceSamplerAlwaysOn                = "always_on"
+	TraceSamplerAlwaysOff               = "always_off"
+	TraceSamplerTraceIDRatio            = "traceidratio"
+	TraceSamplerParentBasedAlwaysOn     = "parentbased_always_on"
+	TraceSamplerParentBasedAlwaysOff    = "parentbased_always_off"
+	TraceSamplerParentBasedTraceIDRatio = "parentbased_traceidratio"
+)
+
+var (
+	availableTraceExporters = sets.NewString(TraceExporterOTLPGRPC, TraceExporterOTLPHTTP, TraceExporterStdout, TraceExporterFile)
+	availableTraceSamplers  = sets.NewString(
+		TraceSamplerAlwaysOn,
+		TraceSamplerAlwaysOff,
+		TraceSamplerTraceIDRatio,
+		TraceSamplerParentBasedAlwaysOn,
+		TraceSamplerParentBasedAlwaysOff,
+		TraceSamplerParentBasedTraceIDRatio,
+	)
+)
+
+// TracingOptions defines options for OpenTelemetry tracing.
+type TracingOptions struct {
+	// Enabled specifies whether the tracing component is started.
+	Enabled bool `json:"enabled" mapstructure:"enabled"`
+	// ServiceName is the service.name resource attribute.
+	ServiceName string `json:"service-name" mapstructure:"service-name"`
+	// Environment is the deployment.environment.name resource attribute, e.g. dev or prod.
+	Environment string `json:"environment" mapstructure:"environment"`
+	// Attributes are additional resource attributes.
+	Attributes map[string]string `json:"attributes" mapstructure:"attributes"`
+	// Exporter is the span exporter: otlp-grpc, otlp-http, stdout or file.
+	Exporter string `json:"exporter" mapstructure:"exporter"`
+	// Endpoint is the host:port of the OTLP collector.
+	Endpoint string `json:"endpoint" mapstructure:"endpoint"`
+	// Insecure disables TLS for the connection to the OTLP collector.
+	Insecure bool `json:"insecure" mapstructure:"insecure"`
+	// Headers are sent with every export request to the OTLP collector, e.g. for authentication.
+	Headers map[string]string `json:"headers" mapstructure:"headers"`
+	// ExportTimeout is the timeout of every export request.
+	ExportTimeout time.Duration `json:"export-timeout" mapstructure:"export-timeout"`
+	// FilePath is the file spans are written to by the file exporter.
+	FilePath string `json:"file-path" mapstructure:"file-path"`
+	// Sampler is the sampler, one of always_on, always_off, traceidratio, parentbased_always_on,
+	// parentbased_always_off and parentbased_traceidratio.
+	Sampler string `json:"sampler" mapstructure:"sampler"`
+	// SampleRatio is the ratio of the traceidratio samplers, between 0 and 1.
+	SampleRatio float64 `json:"sample-ratio" mapstructure:"sample-ratio"`
+}
+
+// NewTracingOptions create a `zero` value instance.
+func NewTracingOptions() *TracingOptions {
+	return &TracingOptions{
+		Environment:   "dev",
+		Exporter:      TraceExporterOTLPGRPC,
+		Endpoint:      "127.0.0.1:4317",
+		Insecure:      true,
+		ExportTimeout: 10 * time.Second,
+		Sampler:       TraceSamplerParentBasedTraceIDRatio,
+		SampleRatio:   1,
+	}
+}
+
+// Validate verifies flags passed to TracingOptions.
+func (o *TracingOptions) Validate() []error {
+	errs := []error{}
+
+	if !o.Enabled {
+		return errs
+	}
+	if !availableTraceExporters.Has(o.Exporter) {
+		errs = append(errs, fmt.Errorf("invalid trace exporter %q: must be one of %v", o.Exporter, availableTraceExporters.List()))
+	}
+	if (o.Exporter == TraceExporterOTLPGRPC || o.Exporter == TraceExporterOTLPHTTP) && o.Endpoint == "" {
+		errs = append(errs, fmt.Errorf("--tracing.endpoint must be set for the %s exporter", o.Exporter))
+	}
+	if o.Exporter == TraceExporterFile && o.FilePath == "" {
+		errs = append(errs, fmt.Errorf("--tracing.file-path must be set for the %s exporter", o.Exporter))
+	}
+	if !availableTraceSamplers.Has(o.Sampler) {
+		errs = append(errs, fmt.Errorf("invalid trace sampler %q: must be one of %v", o.Sampler, availableTraceSamplers.List()))
+	}
+	if o.SampleRatio < 0 || o.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("--tracing.sample-ratio must be between 0 and 1"))
+	}
+
+	return errs
+}
+
+// AddFlags adds flags related to tracing for a specific APIServer to the specified FlagSet.
+func (o *TracingOptions) AddFlags(fs *pflag.FlagSet, prefixes ...string) {
+	fs.BoolVar(&o.Enabled, join(prefixes...)+"tracing.enabled", o.Enabled, "Enable OpenTelemetry tracing.")
+	fs.StringVar(&o.ServiceName, join(prefixes...)+"tracing.service-name", o.ServiceName, "Service name reported in the spans, defaults to the application name.")
+	fs.StringVar(&o.Environment, join(prefixes...)+"tracing.environment", o.Environment, "Deployment environment reported in the spans (dev/test/staging/prod).")
+	fs.StringToStringVar(&o.Attributes, join(prefixes...)+"tracing.attributes", o.Attributes, "Additional resource attributes reported in the spans.")
+	fs.StringVar(&o.Exporter, join(prefixes...)+"tracing.exporter", o.Exporter, fmt.Sprintf("Span exporter, available options: %v", availableTraceExporters.List()))
+	fs.StringVar(&o.Endpoint, join(prefixes...)+"tracing.endpoint", o.Endpoint, "Address (host:port) of the OTLP collector.")
+	fs.BoolVar(&o.Insecure, join(prefixes...)+"tracing.insecure", o.Insecure, "Connect to the OTLP collector without TLS.")
+	fs.StringToStringVar(&o.Headers, join(prefixes...)+"tracing.headers", o.Headers, "Headers sent with every export request to the OTLP collector.")
+	fs.DurationVar(&o.ExportTimeout, join(prefixes...)+"tracing.export-timeout", o.ExportTimeout, "Timeout of every export request.")
+	fs.StringVar(&o.FilePath, join(prefixes...)+"tracing.file-path", o.FilePath, "File spans are written to by the file exporter.")
+	fs.StringVar(&o.Sampler, join(prefixes...)+"tracing.sampler", o.Sampler, fmt.Sprintf("Trace sampler, available options: %v", availableTraceSamplers.List()))
+	fs.Float64Var(&o.SampleRatio, join(prefixes...)+"tracing.sample-ratio", o.SampleRatio, "Ratio of traces sampled by the traceidratio samplers.")
+}
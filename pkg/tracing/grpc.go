@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataCarrier adapts gRPC metadata to propagation.TextMapCarrier.
+type MetadataCarrier metadata.MD
+
+// Get returns the first value of key.
+func (c MetadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// Set sets the value of key.
+func (c MetadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys returns the keys of the metadata.
+func (c MetadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// StartGRPCServerSpan starts a server span for the call of fullMethod, e.g.
+// "/user.v1.UserService/GetUser", continuing the trace propagated in the
+// incoming metadata.
+func StartGRPCServerSpan(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, MetadataCarrier(md))
+
+	return Tracer().Start(ctx, strings.TrimPrefix(fullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(rpcAttributes(fullMethod)...),
+	)
+}
+
+// StartGRPCClientSpan starts a client span for the call of fullMethod and
+// propagates the trace in the outgoing metadata of the returned context.
+func StartGRPCClientSpan(ctx context.Context, fullMethod string) (context.Context, trace.Span) {
+	ctx, span := Tracer().Start(ctx, strings.TrimPrefix(fullMethod, "/"),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(rpcAttributes(fullMethod)...),
+	)
+
+	md, ok := metadata.FromOutgoingContext(ctx)
+	if ok {
+		md = md.Copy()
+	} else {
+		md = metadata.MD{}
+	}
+	otel.GetTextMapPropagator().Inject(ctx, MetadataCarrier(md))
+	return metadata.NewOutgoingContext(ctx, md), span
+}
+
+// EndGRPCSpan records the status code of err on span and ends it.
+func EndGRPCSpan(span trace.Span, err error) {
+	s := status.Convert(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(s.Code())))
+	if err != nil {
+		span.SetAttributes(semconv.ErrorTypeKey.String(s.Code().String()))
+		span.SetStatus(codes.Error, s.Message())
+	}
+	span.End()
+}
+
+func rpcAttributes(fullMethod string) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.RPCSystemGRPC}
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if ok {
+		attrs = append(attrs, semconv.RPCService(service), semconv.RPCMethod(method))
+	}
+	return attrs
+}
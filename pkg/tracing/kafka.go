@@ -0,0 +1,150 @@
+package tracing
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// KafkaHeaderCarrier adapts the headers of a kafka message to
+// propagation.TextMapCarrier, so that the trace is propagated from the
+// producer to the consumers.
+type KafkaHeaderCarrier struct {
+	Message *kafka.Message
+}
+
+// Get returns the value of the header key.
+func (c KafkaHeaderCarrier) Get(key string) string {
+	for _, h := range c.Message.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+	return ""
+}
+
+// Set sets the header key, replacing the existing value if any.
+func (c KafkaHeaderCarrier) Set(key, value string) {
+	for i, h := range c.Message.Headers {
+		if h.Key == key {
+			c.Message.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+	c.Message.Headers = append(c.Message.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Keys returns the keys of the headers.
+func (c KafkaHeaderCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.Message.Headers))
+	for _, h := range c.Message.Headers {
+		keys = append(keys, h.Key)
+	}
+	return keys
+}
+
+// StartKafkaProducerSpan starts a producer span for sending msg to topic and
+// injects the trace into the headers of msg.
+func StartKafkaProducerSpan(ctx context.Context, topic string, msg *kafka.Message) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystemKafka,
+		semconv.MessagingOperationTypeSend,
+		semconv.MessagingOperationName("send"),
+		semconv.MessagingDestinationName(topic),
+	}
+	if len(msg.Key) > 0 {
+		attrs = append(attrs, semconv.MessagingKafkaMessageKey(string(msg.Key)))
+	}
+	ctx, span := Tracer().Start(ctx, "send "+topic,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attrs...),
+	)
+	otel.GetTextMapPropagator().Inject(ctx, KafkaHeaderCarrier{Message: msg})
+	return ctx, span
+}
+
+// StartKafkaConsumerSpan starts a consumer span for processing msg, continuing
+// the trace propagated in its headers. groupID is the consumer group, empty
+// if the reader is not part of a group. The caller ends the span once the
+// message is processed, e.g.
+//
+//	msg, err := reader.FetchMessage(ctx)
+//	...
+//	ctx, span := tracing.StartKafkaConsumerSpan(ctx, msg, groupID)
+//	err = handle(ctx, msg)
+//	if err != nil {
+//		tracing.RecordError(span, err)
+//	}
+//	span.End()
+func StartKafkaConsumerSpan(ctx context.Context, msg kafka.Message, groupID string) (context.Context, trace.Span) {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, KafkaHeaderCarrier{Message: &msg})
+
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystemKafka,
+		semconv.MessagingOperationTypeProcess,
+		semconv.MessagingOperationName("process"),
+		semconv.MessagingDestinationName(msg.Topic),
+		semconv.MessagingDestinationPartitionID(strconv.Itoa(msg.Partition)),
+		semconv.MessagingKafkaOffset(int(msg.Offset)),
+	}
+	if len(msg.Key) > 0 {
+		attrs = append(attrs, semconv.MessagingKafkaMessageKey(string(msg.Key)))
+	}
+	if groupID != "" {
+		attrs = append(attrs, semconv.MessagingConsumerGroupName(groupID))
+	}
+	return Tracer().Start(ctx, "process "+msg.Topic,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+// KafkaWriter wraps a kafka.Writer to create a producer span for every
+// message written and propagate the trace in the message headers.
+type KafkaWriter struct {
+	*kafka.Writer
+}
+
+// NewKafkaWriter returns a KafkaWriter writing messages with w.
+func NewKafkaWriter(w *kafka.Writer) *KafkaWriter {
+	return &KafkaWriter{Writer: w}
+}
+
+// WriteMessages writes msgs like kafka.Writer.WriteMessages. The spans of an
+// async writer end when the messages are queued, not when they are delivered.
+func (w *KafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	spans := make([]trace.Span, len(msgs))
+	for i := range msgs {
+		topic := w.Topic
+		if topic == "" {
+			topic = msgs[i].Topic
+		}
+		_, spans[i] = StartKafkaProducerSpan(ctx, topic, &msgs[i])
+	}
+
+	err := w.Writer.WriteMessages(ctx, msgs...)
+	for i, span := range spans {
+		if msgErr := messageError(err, i); msgErr != nil {
+			RecordError(span, msgErr)
+		}
+		span.End()
+	}
+	return err
+}
+
+// messageError returns the error of the i-th message written, err is either
+// a kafka.WriteErrors holding an error per message or an error of the batch.
+func messageError(err error, i int) error {
+	if errs, ok := err.(kafka.WriteErrors); ok {
+		if i < len(errs) {
+			return errs[i]
+		}
+		return nil
+	}
+	return err
+}
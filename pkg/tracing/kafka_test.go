@@ -0,0 +1,41 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestKafkaPropagation(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	msg := kafka.Message{Key: []byte("user-1"), Headers: []kafka.Header{{Key: "traceparent", Value: []byte("stale")}}}
+	_, producer := StartKafkaProducerSpan(context.Background(), "users", &msg)
+	producer.End()
+	require.Len(t, msg.Headers, 1, "the existing header is replaced")
+
+	msg.Topic, msg.Partition, msg.Offset = "users", 2, 42
+	_, consumer := StartKafkaConsumerSpan(context.Background(), msg, "billing")
+	consumer.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "send users", spans[0].Name())
+	assert.Equal(t, trace.SpanKindProducer, spans[0].SpanKind())
+	assert.Equal(t, "process users", spans[1].Name())
+	assert.Equal(t, trace.SpanKindConsumer, spans[1].SpanKind())
+	assert.Equal(t, spans[0].SpanContext().TraceID(), spans[1].SpanContext().TraceID())
+	assert.Equal(t, spans[0].SpanContext().SpanID(), spans[1].Parent().SpanID())
+	assert.Contains(t, spans[1].Attributes(), semconv.MessagingConsumerGroupName("billing"))
+}
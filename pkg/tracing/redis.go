@@ -0,0 +1,96 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.34.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// redisHook is a go-redis hook creating a client span for every command and
+// pipeline. Only the command names are recorded, the arguments may contain
+// sensitive data.
+type redisHook struct {
+	attrs []attribute.KeyValue
+}
+
+var _ redis.Hook = (*redisHook)(nil)
+
+// RedisHook returns a go-redis hook creating spans for the commands sent to
+// the server at addr.
+func RedisHook(addr string) redis.Hook {
+	attrs := []attribute.KeyValue{semconv.DBSystemNameRedis}
+	if host, port, err := net.SplitHostPort(addr); err == nil {
+		attrs = append(attrs, semconv.ServerAddress(host))
+		if p, err := strconv.Atoi(port); err == nil {
+			attrs = append(attrs, semconv.ServerPort(p))
+		}
+	}
+	return &redisHook{attrs: attrs}
+}
+
+func (h *redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, span := Tracer().Start(ctx, "redis dial",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(h.attrs...),
+		)
+		defer span.End()
+
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			RecordError(span, err)
+		}
+		return conn, err
+	}
+}
+
+func (h *redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := Tracer().Start(ctx, cmd.FullName(),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(h.attrs...),
+			trace.WithAttributes(semconv.DBOperationName(cmd.FullName())),
+		)
+		defer span.End()
+
+		if err := next(ctx, cmd); err != nil {
+			h.recordError(span, err)
+			return err
+		}
+		return nil
+	}
+}
+
+func (h *redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := Tracer().Start(ctx, "pipeline",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(h.attrs...),
+			trace.WithAttributes(
+				semconv.DBOperationName("pipeline"),
+				semconv.DBOperationBatchSize(len(cmds)),
+			),
+		)
+		defer span.End()
+
+		if err := next(ctx, cmds); err != nil {
+			h.recordError(span, err)
+			return err
+		}
+		return nil
+	}
+}
+
+// recordError records err unless it is redis.Nil, which only means that the
+// key does not exist.
+func (h *redisHook) recordError(span trace.Span, err error) {
+	if !errors.Is(err, redis.Nil) {
+		RecordError(span, err)
+	}
+}
@@ -0,0 +1,31 @@
+// Package tracing provides the OpenTelemetry instrumentation shared by the
+// components and clients of the framework.
+//
+// Spans are created with the global tracer provider, which is installed by the
+// tracing component when tracing is enabled. Without it the global provider is
+// a no-op, so the instrumentation costs next to nothing.
+package tracing
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/yanking/micro-zero/pkg/version"
+)
+
+// ScopeName is the instrumentation scope of the spans created by the framework.
+const ScopeName = "github.com/yanking/micro-zero/pkg/tracing"
+
+// Tracer returns the tracer of the framework. The tracer is looked up on
+// every call rather than cached, because the tracing component installs a new
+// global provider every time it is restarted.
+func Tracer() trace.Tracer {
+	return otel.GetTracerProvider().Tracer(ScopeName, trace.WithInstrumentationVersion(version.Get().GitVersion))
+}
+
+// RecordError records err on span and marks the span as failed.
+func RecordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
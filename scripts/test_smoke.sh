@@ -1,4 +1,4 @@
 #!/bin/bash
 
-curl -XGET http://127.0.0.1:5555/healthz
+curl -XGET http://127.0.0.1:20250/healthz?verbose
 